@@ -8,6 +8,7 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -39,7 +40,7 @@ func processJSONLoad(reader io.Reader, pretty bool, opts ...yaml.Option) error {
 		var data any
 		err := loader.Load(&data)
 		if err != nil {
-			if err.Error() == "EOF" {
+			if errors.Is(err, io.EOF) {
 				break
 			}
 			return fmt.Errorf("failed to decode YAML: %w", err)
@@ -67,7 +68,7 @@ func processJSONDecode(reader io.Reader, pretty bool, opts ...yaml.Option) error
 		var data any
 		err := decoder.Decode(&data)
 		if err != nil {
-			if err == io.EOF || err.Error() == "EOF" {
+			if errors.Is(err, io.EOF) {
 				break
 			}
 			return fmt.Errorf("failed to decode YAML: %w", err)
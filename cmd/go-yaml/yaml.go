@@ -7,6 +7,7 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -129,7 +130,7 @@ func processYAMLLoad(reader io.Reader, preserve, marshal, encode bool, opts []ya
 			var data any
 			err := loader.Load(&data)
 			if err != nil {
-				if err == io.EOF || err.Error() == "EOF" {
+				if errors.Is(err, io.EOF) {
 					break
 				}
 				return fmt.Errorf("failed to decode YAML: %w", err)
@@ -233,7 +234,7 @@ func processYAMLDecode(reader io.Reader, preserve, encode bool, opts []yaml.Opti
 			var data any
 			err := decoder.Decode(&data)
 			if err != nil {
-				if err == io.EOF || err.Error() == "EOF" {
+				if errors.Is(err, io.EOF) {
 					break
 				}
 				return fmt.Errorf("failed to decode YAML: %w", err)
@@ -0,0 +1,266 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Arbitrary-precision numbers.
+//
+// The math/big Int, Float and Rat types are loaded from and dumped as YAML
+// numbers, and WithUseNumber loads numbers into interface values as Number,
+// keeping their full precision. Integers too large for int64 and uint64
+// resolve to *big.Int only for those targets; elsewhere they keep resolving
+// to a float, or to a string in hexadecimal and octal forms.
+
+package libyaml
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Number is a YAML integer or float kept as decimal text, like
+// json.Number. Numbers are loaded into interface values as Number with
+// [WithUseNumber], and a Number is dumped as a plain number.
+type Number string
+
+// String returns the literal text of the number.
+func (n Number) String() string {
+	return string(n)
+}
+
+// Int64 returns the number as an int64.
+func (n Number) Int64() (int64, error) {
+	return strconv.ParseInt(string(n), 10, 64)
+}
+
+// Float64 returns the number as a float64.
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+var (
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+	bigRatType   = reflect.TypeOf(big.Rat{})
+)
+
+// number returns a resolved !!int or !!float as a Number.
+func number(n *Node, resolved any) Number {
+	switch v := resolved.(type) {
+	case int:
+		return Number(strconv.Itoa(v))
+	case int64:
+		return Number(strconv.FormatInt(v, 10))
+	case uint64:
+		return Number(strconv.FormatUint(v, 10))
+	case *big.Int:
+		return Number(v.String())
+	case float64:
+		if s, ok := floatText(n.Value, v); ok {
+			return Number(s)
+		}
+		return Number(strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	return Number(n.Value)
+}
+
+// floatText returns the text of a float scalar without underscores, when
+// it is a decimal literal for f. Special values, base 60 floats and other
+// forms report false.
+func floatText(value string, f float64) (string, bool) {
+	s := strings.ReplaceAll(value, "_", "")
+	if v, err := strconv.ParseFloat(s, 64); err != nil || v != f || math.IsInf(v, 0) || math.IsNaN(v) {
+		return "", false
+	}
+	if strings.ContainsAny(s, "xXpP") {
+		return "", false
+	}
+	return s, true
+}
+
+// bigInt returns a resolved integer as a *big.Int.
+func bigInt(resolved any) (*big.Int, bool) {
+	switch v := resolved.(type) {
+	case int:
+		return big.NewInt(int64(v)), true
+	case int64:
+		return big.NewInt(v), true
+	case uint64:
+		return new(big.Int).SetUint64(v), true
+	case *big.Int:
+		return v, true
+	}
+	return nil, false
+}
+
+// bigFloat returns a resolved float as a *big.Float, parsed from the scalar
+// text when possible so that no precision is lost.
+func bigFloat(n *Node, resolved any) (*big.Float, bool) {
+	f, ok := resolved.(float64)
+	if !ok || math.IsNaN(f) {
+		return nil, false
+	}
+	if math.IsInf(f, 0) {
+		return new(big.Float).SetInf(f < 0), true
+	}
+	if s, ok := floatText(n.Value, f); ok {
+		prec := uint(len(s)) * 4
+		if prec < 64 {
+			prec = 64
+		}
+		if z, _, err := big.ParseFloat(s, 10, prec, big.ToNearestEven); err == nil {
+			return z, true
+		}
+	}
+	return new(big.Float).SetFloat64(f), true
+}
+
+// wantsBigInts reports whether integers too large for int64 and uint64 are
+// resolved as *big.Int when constructing into out: out is a big.Int,
+// big.Float or big.Rat, or an interface holding a Number with UseNumber.
+// Other targets keep the float or string such integers resolve to.
+func (c *Constructor) wantsBigInts(out reflect.Value) bool {
+	switch out.Type() {
+	case bigIntType, bigFloatType, bigRatType:
+		return true
+	}
+	return c.useNumber && out.Kind() == reflect.Interface && out.NumMethod() == 0
+}
+
+// constructBig constructs a resolved !!int or !!float into a big.Int,
+// big.Float or big.Rat. It reports whether out is one of those types and
+// the tag is numeric; other values are constructed as usual.
+func (c *Constructor) constructBig(n *Node, tag string, resolved any, out reflect.Value) (handled, good bool) {
+	if tag != intTag && tag != floatTag || !out.CanAddr() {
+		return false, false
+	}
+	switch out.Type() {
+	case bigIntType:
+		z := out.Addr().Interface().(*big.Int)
+		if i, ok := bigInt(resolved); ok {
+			z.Set(i)
+			return true, true
+		}
+		if f, ok := bigFloat(n, resolved); ok && f.IsInt() {
+			f.Int(z)
+			return true, true
+		}
+	case bigFloatType:
+		z := out.Addr().Interface().(*big.Float)
+		if i, ok := bigInt(resolved); ok {
+			if z.Prec() == 0 && i.BitLen() > 64 {
+				z.SetPrec(uint(i.BitLen()))
+			}
+			z.SetInt(i)
+			return true, true
+		}
+		if f, ok := bigFloat(n, resolved); ok {
+			z.Set(f)
+			return true, true
+		}
+	case bigRatType:
+		z := out.Addr().Interface().(*big.Rat)
+		if i, ok := bigInt(resolved); ok {
+			z.SetInt(i)
+			return true, true
+		}
+		if f, ok := resolved.(float64); ok {
+			if s, ok := floatText(n.Value, f); ok {
+				if _, ok := z.SetString(s); ok {
+					return true, true
+				}
+			}
+			if !math.IsInf(f, 0) && !math.IsNaN(f) {
+				z.SetFloat64(f)
+				return true, true
+			}
+		}
+	default:
+		return false, false
+	}
+	c.tagError(n, tag, out)
+	return true, false
+}
+
+// bigv represents a math/big number, or a Number, as a YAML number.
+func (r *Representer) bigv(tag string, in any) *Node {
+	var s, rtag string
+	switch v := in.(type) {
+	case *big.Int:
+		s, rtag = v.String(), intTag
+	case *big.Float:
+		s, rtag = v.Text('g', -1), floatTag
+		if v.IsInf() {
+			s = ".inf"
+			if v.Sign() < 0 {
+				s = "-.inf"
+			}
+		}
+	case *big.Rat:
+		if v.IsInt() {
+			s, rtag = v.Num().String(), intTag
+		} else if digits, ok := ratDecimals(v); ok {
+			s, rtag = v.FloatString(digits), floatTag
+		} else {
+			// Not a finite decimal: write it as a string like 1/3,
+			// which big.Rat reads back.
+			return r.stringv(tag, reflect.ValueOf(v.String()))
+		}
+	case Number:
+		s, rtag = numberText(v)
+	}
+	if tag == "" {
+		tag = rtag
+	}
+	return &Node{
+		Kind:  ScalarNode,
+		Tag:   tag,
+		Value: s,
+	}
+}
+
+// numberText returns the YAML text and tag of a Number.
+func numberText(n Number) (string, string) {
+	s := string(n)
+	if _, ok := new(big.Int).SetString(s, 10); ok {
+		return s, intTag
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		failDumpf(RepresenterStage, "invalid number %q", s)
+	}
+	switch {
+	case math.IsNaN(f):
+		return ".nan", floatTag
+	case math.IsInf(f, 0) && !strings.ContainsAny(s, "0123456789"):
+		if f < 0 {
+			return "-.inf", floatTag
+		}
+		return ".inf", floatTag
+	}
+	return s, floatTag
+}
+
+// ratDecimals returns the number of decimals needed to write r exactly, and
+// whether r is a finite decimal at all.
+func ratDecimals(r *big.Rat) (int, bool) {
+	d := new(big.Int).Set(r.Denom())
+	twos := int(d.TrailingZeroBits())
+	d.Rsh(d, uint(twos))
+	fives := 0
+	five := big.NewInt(5)
+	m := new(big.Int)
+	for d.Cmp(big.NewInt(1)) != 0 {
+		d.QuoRem(d, five, m)
+		if m.Sign() != 0 {
+			return 0, false
+		}
+		fives++
+	}
+	if twos > fives {
+		return twos, true
+	}
+	return fives, true
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for arbitrary-precision numbers.
+
+package libyaml
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+func bigIntString(s string) *big.Int {
+	i, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		panic("invalid big.Int " + s)
+	}
+	return i
+}
+
+func TestResolveBigInt(t *testing.T) {
+	for _, schema := range []Schema{DefaultSchema, CoreSchema, JSONSchema, YAML11Schema} {
+		tag, v := schema.resolveNumbers("", "-123456789012345678901234567890", true)
+		assert.Equalf(t, intTag, tag, "%s", schema)
+		assert.DeepEqualf(t, bigIntString("-123456789012345678901234567890"), v, "%s", schema)
+
+	}
+
+	// Without bigInts, overflowing integers resolve as before.
+	for _, schema := range []Schema{DefaultSchema, CoreSchema, JSONSchema} {
+		tag, v := schema.resolve("", "-123456789012345678901234567890")
+		assert.Equalf(t, floatTag, tag, "%s", schema)
+		assert.Equalf(t, -1.2345678901234568e29, v, "%s", schema)
+	}
+	tag, v := resolveNumbers("", "0x1_FFFF_FFFF_FFFF_FFFF", true)
+	assert.Equal(t, intTag, tag)
+	assert.DeepEqual(t, bigIntString("0x1FFFFFFFFFFFFFFFF"), v)
+
+	tag, v = resolve("", "0x1_FFFF_FFFF_FFFF_FFFF")
+	assert.Equal(t, strTag, tag)
+	assert.Equal(t, "0x1_FFFF_FFFF_FFFF_FFFF", v)
+
+	tag, v = resolveNumbers("!!float", "123456789012345678901234567890", true)
+	assert.Equal(t, floatTag, tag)
+	assert.Equal(t, 1.2345678901234568e29, v)
+}
+
+func TestLoadBigNumbers(t *testing.T) {
+	var v struct {
+		I  big.Int
+		P  *big.Int
+		F  *big.Float
+		R  *big.Rat
+		Q  *big.Rat
+		FI *big.Float
+		X  float64
+	}
+	src := "i: 123456789012345678901234567890\n" +
+		"p: 0x10\n" +
+		"f: 3.14159265358979323846264338327950288\n" +
+		"r: 0.1\n" +
+		"q: '1/3'\n" +
+		"fi: 1_000\n" +
+		"x: 123456789012345678901234567890\n"
+	assert.NoError(t, Load([]byte(src), &v))
+	assert.Equal(t, "123456789012345678901234567890", v.I.String())
+	assert.Equal(t, "16", v.P.String())
+	assert.Equal(t, "3.14159265358979323846264338327950288", v.F.Text('f', 35))
+	assert.Equal(t, "1/10", v.R.String())
+	assert.Equal(t, "1/3", v.Q.String())
+	assert.Equal(t, "1000", v.FI.String())
+	assert.Equal(t, 1.2345678901234568e29, v.X)
+
+	var i struct{ I *big.Int }
+	assert.NoError(t, Load([]byte("i: 1e3"), &i))
+	assert.Equal(t, "1000", i.I.String())
+	err := Load([]byte("i: 1.5"), &i)
+	assert.ErrorMatches(t, "yaml: construct errors: line 1: cannot construct !!float `1.5` into big.Int", err)
+
+	var r struct{ R big.Rat }
+	err = Load([]byte("r: .nan"), &r)
+	assert.ErrorMatches(t, "yaml: construct errors: line 1: cannot construct !!float `.nan` into big.Rat", err)
+
+	// Interface values keep the float and string of earlier releases.
+	var a map[string]any
+	assert.NoError(t, Load([]byte("a: 123456789012345678901234567890\nb: 0xFFFFFFFFFFFFFFFFFFFF"), &a))
+	assert.DeepEqual(t, map[string]any{"a": 1.2345678901234568e29, "b": "0xFFFFFFFFFFFFFFFFFFFF"}, a)
+
+	var h struct{ H *big.Int }
+	assert.NoError(t, Load([]byte("h: 0xFFFFFFFFFFFFFFFFFFFF"), &h))
+	assert.Equal(t, "1208925819614629174706175", h.H.String())
+
+	var n struct{ N int64 }
+	err = Load([]byte("n: 123456789012345678901234567890"), &n)
+	assert.ErrorMatches(t, "yaml: construct errors: line 1: cannot construct !!float `1234567...` into int64", err)
+}
+
+func TestLoadUseNumber(t *testing.T) {
+	src := "a: 1\nb: 1.10\nc: 123456789012345678901234567890\nd: 0x1F\ne: -.inf\nf: 1_000.5\ng: abc\nh: '2'\n"
+	var v map[string]any
+	assert.NoError(t, Load([]byte(src), &v, WithUseNumber()))
+	assert.DeepEqual(t, map[string]any{
+		"a": Number("1"),
+		"b": Number("1.10"),
+		"c": Number("123456789012345678901234567890"),
+		"d": Number("31"),
+		"e": Number("-Inf"),
+		"f": Number("1000.5"),
+		"g": "abc",
+		"h": "2",
+	}, v)
+
+	i, err := v["d"].(Number).Int64()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(31), i)
+	f, err := v["e"].(Number).Float64()
+	assert.NoError(t, err)
+	assert.True(t, math.IsInf(f, -1))
+
+	// Typed targets are not affected.
+	var s struct{ A int }
+	assert.NoError(t, Load([]byte(src), &s, WithUseNumber()))
+	assert.Equal(t, 1, s.A)
+}
+
+func TestDumpBigNumbers(t *testing.T) {
+	v := struct {
+		I  *big.Int
+		V  big.Int
+		F  *big.Float
+		FI *big.Float
+		R  *big.Rat
+		RI *big.Rat
+		RQ *big.Rat
+		N  []Number
+	}{
+		I:  bigIntString("-123456789012345678901234567890"),
+		V:  *big.NewInt(7),
+		F:  new(big.Float).SetInf(true),
+		FI: big.NewFloat(2.5),
+		R:  big.NewRat(3, 8),
+		RI: big.NewRat(6, 3),
+		RQ: big.NewRat(1, 3),
+		N:  []Number{"1", "1.10", "+Inf", "1e400"},
+	}
+	out, err := Dump(&v)
+	assert.NoError(t, err)
+	assert.Equal(t, "i: -123456789012345678901234567890\n"+
+		"v: 7\n"+
+		"f: -.inf\n"+
+		"fi: 2.5\n"+
+		"r: 0.375\n"+
+		"ri: 2\n"+
+		"rq: 1/3\n"+
+		"'n':\n- 1\n- 1.10\n- .inf\n- 1e400\n", string(out))
+
+	out2, err := Dump(map[string]string{"a": "0xFFFFFFFFFFFFFFFFFFFF"})
+	assert.NoError(t, err)
+	assert.Equal(t, "a: 0xFFFFFFFFFFFFFFFFFFFF\n", string(out2))
+
+	_, err = Dump(Number("twelve"))
+	assert.ErrorMatches(t, `.*invalid number "twelve"`, err)
+
+	// Dumped values load back.
+	var back struct {
+		I  *big.Int
+		R  *big.Rat
+		RQ *big.Rat
+	}
+	assert.NoError(t, Load(out, &back))
+	assert.Equal(t, v.I.String(), back.I.String())
+	assert.Equal(t, "3/8", back.R.String())
+	assert.Equal(t, "1/3", back.RQ.String())
+}
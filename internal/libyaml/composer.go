@@ -25,6 +25,18 @@ type Composer struct {
 	atStreamEnd  bool     // at stream end
 	encoding     Encoding // stream encoding from STREAM_START
 	opts         *Options // options for loading
+
+	// Counts checked against the resource limits.
+	nodeCount     int // nodes in the current document
+	anchorCount   int // anchors in the current document
+	documentCount int // documents in the stream
+	includedBytes int // bytes of files included by a node plugin
+
+	eventHook EventFunc // event plugin between the Parser and the Composer
+	pending   []Event   // events passed on by the event hook, not yet composed
+
+	// version is the %YAML directive of the last composed document.
+	version *VersionDirective
 }
 
 // NewComposer creates a new composer from a byte slice.
@@ -39,6 +51,8 @@ func NewComposer(b []byte, opts *Options) *Composer {
 	p.Parser.SetInputString(b)
 	if opts != nil {
 		p.Parser.depthCheck = opts.DepthCheck
+		p.Parser.limits = opts.ResourceLimits
+		p.eventHook = opts.EventHook
 	}
 	return &p
 }
@@ -52,6 +66,8 @@ func NewComposerFromReader(r io.Reader, opts *Options) *Composer {
 	p.Parser.SetInputReader(r)
 	if opts != nil {
 		p.Parser.depthCheck = opts.DepthCheck
+		p.Parser.limits = opts.ResourceLimits
+		p.eventHook = opts.EventHook
 	}
 	return &p
 }
@@ -103,6 +119,13 @@ func (c *Composer) Compose() *Node {
 	case TAIL_COMMENT_EVENT:
 		panic("internal error: unexpected tail comment event (please report)")
 	default:
+		if c.eventHook != nil {
+			// The event plugin passed on an invalid event stream.
+			Fail(formatComposerError(
+				fmt.Sprintf("unexpected %s event", c.event.Type),
+				Mark{Line: c.event.StartMark.Line, Column: c.event.StartMark.Column},
+			))
+		}
 		panic("internal error: attempted to parse unknown event (please report): " + c.event.Type.String())
 	}
 }
@@ -131,6 +154,11 @@ func (c *Composer) node(kind Kind, tag, value string) *Node {
 		n.LineComment = string(c.event.LineComment)
 		n.FootComment = string(c.event.FootComment)
 	}
+	if kind == DocumentNode {
+		c.countDocument(n)
+	} else {
+		c.countNode(n)
+	}
 	return n
 }
 
@@ -139,6 +167,7 @@ func (c *Composer) node(kind Kind, tag, value string) *Node {
 func (c *Composer) document() *Node {
 	n := c.node(DocumentNode, "", "")
 	c.doc = n
+	c.version = c.event.GetVersionDirective()
 	c.expect(DOCUMENT_START_EVENT)
 	c.parseChild(n)
 	if c.peek() == DOCUMENT_END_EVENT {
@@ -241,6 +270,7 @@ func (c *Composer) mapping() *Node {
 	c.expect(MAPPING_START_EVENT)
 	for c.peek() != MAPPING_END_EVENT {
 		k := c.parseChild(n)
+		c.checkMappingKeys(n, k)
 		if block && k.FootComment != "" {
 			// Must be a foot comment for the prior value when being dedented.
 			if len(n.Content) > 2 {
@@ -308,9 +338,7 @@ func (c *Composer) SetStreamNodes(enable bool) {
 // checks that it's of the expected type.
 func (c *Composer) expect(e EventType) {
 	if c.event.Type == NO_EVENT {
-		if err := c.Parser.Parse(&c.event); err != nil {
-			c.fail(err)
-		}
+		c.nextEvent()
 	}
 	if c.event.Type == STREAM_END_EVENT {
 		Fail(formatComposerError(
@@ -337,9 +365,7 @@ func (c *Composer) peek() EventType {
 	// It's curious choice from the underlying API to generally return a
 	// positive result on success, but on this case return true in an error
 	// scenario. This was the source of bugs in the past (issue #666).
-	if err := c.Parser.Parse(&c.event); err != nil {
-		c.fail(err)
-	}
+	c.nextEvent()
 	return c.event.Type
 }
 
@@ -353,6 +379,7 @@ func (c *Composer) anchor(n *Node, anchor []byte) {
 	if anchor != nil {
 		n.Anchor = string(anchor)
 		c.anchors[n.Anchor] = n
+		c.countAnchor(n)
 	}
 }
 
@@ -10,8 +10,10 @@ package libyaml
 import (
 	"encoding"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"reflect"
 	"time"
 )
@@ -50,10 +52,22 @@ type Constructor struct {
 
 	KnownFields    bool
 	UniqueKeys     bool
+	structOpts     structOptions
+	schema         Schema
 	constructCount int
-	aliasCount     int
-	aliasDepth     int
-	aliasCheck     func(aliasCount, constructCount int) error
+
+	caseInsensitiveKeys bool
+	deprecatedKey       DeprecatedKeyFunc
+	aliasCount          int
+	aliasDepth          int
+	aliasCheck          func(aliasCount, constructCount int) error
+	useNumber           bool
+	orderedMaps         bool
+	tagConstruct        TagConstructFunc
+	implicitTag         func(value string) string
+	discriminators      map[reflect.Type]*discriminator
+	collectErrors       bool
+	maxErrors           int
 
 	mergedFields map[any]bool
 }
@@ -62,12 +76,23 @@ type Constructor struct {
 // options.
 func NewConstructor(opts *Options) *Constructor {
 	return &Constructor{
-		stringMapType:  stringMapType,
-		generalMapType: generalMapType,
-		KnownFields:    opts.KnownFields,
-		UniqueKeys:     opts.UniqueKeys,
-		aliases:        make(map[*Node]bool),
-		aliasCheck:     opts.AliasCheck,
+		stringMapType:       stringMapType,
+		generalMapType:      generalMapType,
+		KnownFields:         opts.KnownFields,
+		UniqueKeys:          opts.UniqueKeys,
+		structOpts:          newStructOptions(opts),
+		schema:              opts.Schema,
+		caseInsensitiveKeys: opts.CaseInsensitiveKeys,
+		deprecatedKey:       opts.DeprecatedKey,
+		aliases:             make(map[*Node]bool),
+		aliasCheck:          opts.AliasCheck,
+		useNumber:           opts.UseNumber,
+		orderedMaps:         opts.OrderedMaps,
+		tagConstruct:        opts.TagConstruct,
+		implicitTag:         opts.ImplicitTag,
+		discriminators:      opts.Discriminators,
+		collectErrors:       opts.CollectErrors,
+		maxErrors:           opts.MaxErrors,
 	}
 }
 
@@ -88,6 +113,9 @@ func (c *Constructor) Construct(n *Node, out reflect.Value) (good bool) {
 			Fail(formatConstructorError(err, Mark{Line: n.Line, Column: n.Column}))
 		}
 	}
+	if c.maxErrors > 0 && len(c.TypeErrors) > c.maxErrors {
+		Fail(c.takeErrors())
+	}
 	if out.Type() == nodeType {
 		out.Set(reflect.ValueOf(n).Elem())
 		return true
@@ -100,6 +128,20 @@ func (c *Constructor) Construct(n *Node, out reflect.Value) (good bool) {
 		return c.alias(n, out)
 	}
 
+	if c.tagConstruct != nil && !isCoreTag(n.Tag) {
+		if handled, good := c.constructTag(n, out); handled {
+			return good
+		}
+	}
+
+	if out.Kind() == reflect.Interface && c.discriminators != nil && n.ShortTag() != nullTag {
+		if d := c.discriminators[out.Type()]; d != nil {
+			if handled, good := c.discriminated(d, n, out); handled {
+				return good
+			}
+		}
+	}
+
 	out, constructed, good := c.prepare(n, out)
 	if constructed {
 		return good
@@ -287,6 +329,10 @@ func (c *Constructor) constructInt(n *Node, resolved any, out reflect.Value) boo
 		case uint64:
 			out.SetFloat(float64(resolved))
 			return true
+		case *big.Int:
+			f, _ := new(big.Float).SetInt(resolved).Float64()
+			out.SetFloat(f)
+			return true
 		}
 	case reflect.String:
 		// Allow int to string conversion
@@ -475,8 +521,13 @@ func (c *Constructor) scalar(n *Node, out reflect.Value) bool {
 	if n.indicatedString() {
 		tag = strTag
 		resolved = n.Value
+	} else if c.implicitTag != nil && n.Style&TaggedStyle == 0 && !isCoreTag(n.Tag) {
+		// Plain scalars given an application tag by a resolver plugin
+		// are strings unless a tag plugin constructed them.
+		tag = strTag
+		resolved = n.Value
 	} else {
-		tag, resolved = resolve(n.Tag, n.Value)
+		tag, resolved = c.schema.resolveNumbers(n.Tag, n.Value, c.wantsBigInts(out))
 		if tag == binaryTag {
 			data, err := base64.StdEncoding.DecodeString(resolved.(string))
 			if err != nil {
@@ -494,6 +545,16 @@ func (c *Constructor) scalar(n *Node, out reflect.Value) bool {
 		return c.null(out)
 	}
 
+	// Numbers into big.Int, big.Float, big.Rat or, with UseNumber, any
+	if handled, good := c.constructBig(n, tag, resolved, out); handled {
+		return good
+	}
+	if c.useNumber && (tag == intTag || tag == floatTag) &&
+		out.Kind() == reflect.Interface && out.NumMethod() == 0 {
+		out.Set(reflect.ValueOf(number(n, resolved)))
+		return true
+	}
+
 	// Fast path: exact type match
 	if resolvedv := reflect.ValueOf(resolved); out.Type() == resolvedv.Type() {
 		out.Set(resolvedv)
@@ -553,10 +614,11 @@ func (c *Constructor) sequence(n *Node, out reflect.Value) (good bool) {
 		out.Set(reflect.MakeSlice(out.Type(), l, l))
 	case reflect.Array:
 		if l != out.Len() {
-			Fail(formatConstructorError(
+			c.fail(formatConstructorError(
 				fmt.Errorf("invalid array: want %d elements but got %d", out.Len(), l),
 				Mark{Line: n.Line, Column: n.Column},
 			))
+			return false
 		}
 	case reflect.Interface:
 		// No type hints. Will have to use a generic sequence.
@@ -604,10 +666,13 @@ func (c *Constructor) mapping(n *Node, out reflect.Value) (good bool) {
 				}
 			}
 		}
-		if len(c.TypeErrors) > nerrs {
+		if len(c.TypeErrors) > nerrs && !c.collectErrors {
 			return false
 		}
 	}
+	if out.Type() == mapSliceType {
+		return c.mappingSlice(n, out)
+	}
 	switch out.Kind() {
 	case reflect.Struct:
 		return c.mappingStruct(n, out)
@@ -615,6 +680,12 @@ func (c *Constructor) mapping(n *Node, out reflect.Value) (good bool) {
 		// okay
 	case reflect.Interface:
 		iface := out
+		if c.orderedMaps {
+			m := reflect.New(mapSliceType).Elem()
+			good = c.mappingSlice(n, m)
+			iface.Set(m)
+			return good
+		}
 		if isStringMap(n) {
 			out = reflect.MakeMap(c.stringMapType)
 		} else {
@@ -669,10 +740,11 @@ func (c *Constructor) mapping(n *Node, out reflect.Value) (good bool) {
 				kkind = k.Elem().Kind()
 			}
 			if kkind == reflect.Map || kkind == reflect.Slice {
-				Fail(formatConstructorError(
+				c.fail(formatConstructorError(
 					fmt.Errorf("cannot use '%#v' as a map key; try decoding into yaml.Node", k.Interface()),
 					Mark{Line: n.Content[i].Line, Column: n.Content[i].Column},
 				))
+				continue
 			}
 			e := reflect.New(et).Elem()
 			if c.Construct(n.Content[i+1], e) || n.Content[i+1].ShortTag() == nullTag && (mapIsNew || !out.MapIndex(k).IsValid()) {
@@ -698,7 +770,7 @@ func (c *Constructor) mapping(n *Node, out reflect.Value) (good bool) {
 // It handles field matching by name, inline fields, inline maps, merge keys,
 // and enforces known fields and unique keys when configured.
 func (c *Constructor) mappingStruct(n *Node, out reflect.Value) (good bool) {
-	sinfo, err := getStructInfo(out.Type())
+	sinfo, err := getStructInfo(out.Type(), c.structOpts)
 	if err != nil {
 		panic(err)
 	}
@@ -734,19 +806,29 @@ func (c *Constructor) mappingStruct(n *Node, out reflect.Value) (good bool) {
 			continue
 		}
 		sname := name.String()
+		info, alias, ok := sinfo.field(sname, c.caseInsensitiveKeys)
 		if mergedFields != nil {
-			if mergedFields[sname] {
+			key := sname
+			if ok {
+				key = info.Key
+			}
+			if mergedFields[key] {
 				continue
 			}
-			mergedFields[sname] = true
+			mergedFields[key] = true
 		}
-		if info, ok := sinfo.FieldsMap[sname]; ok {
+		if ok && alias && c.deprecatedKey != nil {
+			c.deprecatedKey(sname, info.Key, Mark{Line: ni.Line, Column: ni.Column})
+		}
+		if ok {
 			if c.UniqueKeys {
 				if doneFields[info.Id] {
-					c.TypeErrors = append(c.TypeErrors, formatConstructorError(
-						fmt.Errorf("field %s already set in type %s", name.String(), out.Type()),
-						Mark{Line: ni.Line, Column: ni.Column},
-					))
+					if !repeatedKey(n, i) {
+						c.TypeErrors = append(c.TypeErrors, formatConstructorError(
+							fmt.Errorf("field %s already set in type %s", name.String(), out.Type()),
+							Mark{Line: ni.Line, Column: ni.Column},
+						))
+					}
 					continue
 				}
 				doneFields[info.Id] = true
@@ -757,7 +839,14 @@ func (c *Constructor) mappingStruct(n *Node, out reflect.Value) (good bool) {
 			} else {
 				field = c.fieldByIndex(n, out, info.Inline)
 			}
-			c.Construct(n.Content[i+1], field)
+			value := n.Content[i+1]
+			if info.String && value.Kind == ScalarNode && value.ShortTag() == strTag && stringKind(field.Type()) {
+				// Resolve the quoted value as if it were plain.
+				plain := *value
+				plain.Tag, plain.Style = "", 0
+				value = &plain
+			}
+			c.Construct(value, field)
 		} else if sinfo.InlineMap != -1 {
 			if inlineMap.IsNil() {
 				inlineMap.Set(reflect.MakeMap(inlineMap.Type()))
@@ -775,11 +864,82 @@ func (c *Constructor) mappingStruct(n *Node, out reflect.Value) (good bool) {
 
 	c.mergedFields = mergedFields
 	if mergeNode != nil {
+		if mergedFields == nil {
+			// Record the keys set here by field, so that merged mappings
+			// using another alias or case of a key don't override them.
+			c.mergedFields = c.structKeys(n, sinfo)
+		}
 		c.merge(n, mergeNode, out)
+		c.mergedFields = mergedFields
+	}
+	// Mappings merged into a struct only supply some of its keys, so
+	// missing keys are checked on the mapping holding the << key.
+	if mergedFields == nil && len(sinfo.PresenceFields) > 0 {
+		c.missingFields(n, out, sinfo)
 	}
 	return true
 }
 
+// missingFields reports the required fields of a struct whose key is
+// missing from mapping n, keys merged with << included, and constructs the
+// default values of the missing fields that are still zero.
+func (c *Constructor) missingFields(n *Node, out reflect.Value, sinfo *structInfo) {
+	present := c.structKeys(&Node{Kind: MappingNode, Content: mappingEntries(n)}, sinfo)
+	for _, info := range sinfo.PresenceFields {
+		if present[info.Key] {
+			continue
+		}
+		if info.Required {
+			c.TypeErrors = append(c.TypeErrors, formatConstructorError(
+				fmt.Errorf("required field %s not found in type %s", info.Key, out.Type()),
+				Mark{Line: n.Line, Column: n.Column},
+			))
+			continue
+		}
+		var field reflect.Value
+		if info.Inline == nil {
+			field = out.Field(info.Num)
+		} else {
+			field = c.fieldByIndex(n, out, info.Inline)
+		}
+		if !field.IsValid() || !field.IsZero() {
+			continue
+		}
+		// Errors in the default value are reported at the mapping.
+		c.Construct(copyAt(info.Default, n.Line, n.Column), field)
+	}
+}
+
+// structKeys returns the keys of mapping n, excluding merge keys, with the
+// keys of struct fields replaced by the key of their field.
+func (c *Constructor) structKeys(n *Node, sinfo *structInfo) map[any]bool {
+	keys := make(map[any]bool)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key := derefAlias(n.Content[i])
+		if key.Kind != ScalarNode || isMerge(key) {
+			continue
+		}
+		if info, _, ok := sinfo.field(key.Value, c.caseInsensitiveKeys); ok {
+			keys[info.Key] = true
+		} else {
+			keys[key.Value] = true
+		}
+	}
+	return keys
+}
+
+// copyAt returns a deep copy of n with every node positioned at line and
+// column.
+func copyAt(n *Node, line, column int) *Node {
+	c := *n
+	c.Line, c.Column = line, column
+	c.Content = make([]*Node, len(n.Content))
+	for i, child := range n.Content {
+		c.Content[i] = copyAt(child, line, column)
+	}
+	return &c
+}
+
 // merge processes a merge key (<<) by constructing the merge value into out.
 // The merge value can be a single mapping, an alias to a mapping, or a
 // sequence of mappings.
@@ -823,6 +983,19 @@ func (c *Constructor) merge(parent *Node, merge *Node, out reflect.Value) {
 	c.mergedFields = mergedFields
 }
 
+// repeatedKey reports whether the key at index i of mapping n repeats an
+// earlier key. Such keys are already reported by the UniqueKeys check of
+// mapping.
+func repeatedKey(n *Node, i int) bool {
+	ni := n.Content[i]
+	for j := 0; j < i; j += 2 {
+		if nj := n.Content[j]; nj.Kind == ni.Kind && nj.Value == ni.Value {
+			return true
+		}
+	}
+	return false
+}
+
 // isStringMap checks if a MappingNode has only string or merge keys.
 // This determines whether to use map[string]any or map[any]any when
 // constructing into an interface{}.
@@ -1039,6 +1212,31 @@ func (c *Constructor) callLegacyConstructor(n *Node, u legacyConstructor) (good
 	}
 }
 
+// fail reports an error that stops loading, unless errors are collected
+// with WithCollectErrors. In that case it is recorded with the type errors
+// and the caller skips the offending value.
+func (c *Constructor) fail(err *LoadError) {
+	if !c.collectErrors {
+		Fail(err)
+	}
+	c.TypeErrors = append(c.TypeErrors, err)
+}
+
+// errTooManyErrors ends the errors of a load stopped by WithMaxErrors.
+var errTooManyErrors = errors.New("too many errors")
+
+// takeErrors returns the errors recorded while constructing a document,
+// cut to the limit set with WithMaxErrors, and clears them.
+func (c *Constructor) takeErrors() *LoadErrors {
+	errs := c.TypeErrors
+	c.TypeErrors = nil
+	if c.maxErrors > 0 && len(errs) > c.maxErrors {
+		errs = append(errs[:c.maxErrors:c.maxErrors],
+			formatConstructorError(errTooManyErrors, errs[c.maxErrors].Mark))
+	}
+	return &LoadErrors{Errors: errs}
+}
+
 // tagError records a type construction error indicating that a node with a
 // given tag cannot be constructed into the target type.
 func (c *Constructor) tagError(n *Node, tag string, out reflect.Value) {
@@ -80,7 +80,12 @@ func (d *Desolver) desolveScalar(n *Node) {
 		}
 		return
 	default:
-		// Custom tag - preserve it
+		// Custom tag - preserve it, unless a resolver plugin gives the
+		// plain value that tag anyway
+		if n.Style&(SingleQuotedStyle|DoubleQuotedStyle|LiteralStyle|FoldedStyle) == 0 &&
+			d.resolve(n.Value) == n.Tag {
+			n.Tag = ""
+		}
 		return
 	}
 
@@ -90,7 +95,7 @@ func (d *Desolver) desolveScalar(n *Node) {
 	}
 
 	// What tag would this value resolve to?
-	rtag, _ := resolve("", n.Value)
+	rtag := d.resolve(n.Value)
 
 	// If resolved tag matches current tag, we can elide the tag
 	if rtag == stag {
@@ -125,6 +130,15 @@ func (d *Desolver) desolveScalar(n *Node) {
 	// For other standard tags with mismatches, keep the tag to preserve type
 }
 
+// resolve returns the tag a plain scalar with the given value resolves to
+// when loaded with the same options.
+func (d *Desolver) resolve(value string) string {
+	if d.opts == nil {
+		return resolvePlain(DefaultSchema, nil, value)
+	}
+	return resolvePlain(d.opts.Schema, d.opts.ImplicitTag, value)
+}
+
 // desolveCollection removes default tags from collection nodes.
 func (d *Desolver) desolveCollection(n *Node) {
 	// If explicitly tagged by user, keep it
@@ -0,0 +1,499 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Structural comparison of Node trees.
+//
+// Diff walks two trees side by side and reports what was added, removed or
+// changed, keyed by the path of each change. Scalars are compared by their
+// resolved tag and value rather than their text, so 0x10 and 16 are equal
+// and so are "yes" and 'yes'. Aliases are followed and << merge keys are
+// expanded before comparing, so two documents that load to the same data
+// compare equal regardless of how they were written.
+
+package libyaml
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChangeKind identifies the kind of difference reported by [Diff].
+type ChangeKind int
+
+const (
+	ChangeAdded     ChangeKind = iota + 1 // Present only in the new tree
+	ChangeRemoved                         // Present only in the old tree
+	ChangeModified                        // Present in both with different content
+	ChangeReordered                       // Mapping with the same keys in a different order
+)
+
+// String returns the lowercase name of the change kind.
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "changed"
+	case ChangeReordered:
+		return "reordered"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change describes a single difference between two node trees.
+type Change struct {
+	Kind ChangeKind
+
+	// Path locates the change as a path expression accepted by
+	// [CompilePath]. Sequence indexes count items in the old tree, except
+	// for the final index of an added item, which counts in the new tree.
+	Path string
+
+	// From and To are the differing nodes in the old and new tree, with
+	// aliases followed. From is nil for additions and To is nil for
+	// removals.
+	From *Node
+	To   *Node
+
+	// FromMark and ToMark give the source positions of the change. For an
+	// addition FromMark points at the collection in the old tree that lacks
+	// the node, and likewise ToMark for a removal.
+	FromMark Mark
+	ToMark   Mark
+}
+
+// String returns a one-line description of the change.
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s", c.Kind, c.Path)
+}
+
+// DiffOption configures [Diff].
+type DiffOption func(*diffOptions)
+
+type diffOptions struct {
+	keyOrder bool
+}
+
+// DiffKeyOrder makes [Diff] report mappings whose common keys appear in a
+// different order as [ChangeReordered]. By default key order is ignored.
+func DiffKeyOrder() DiffOption {
+	return func(o *diffOptions) {
+		o.keyOrder = true
+	}
+}
+
+// Diff compares two node trees and returns the differences from a to b in
+// document order. It returns no changes if the trees hold equal data.
+// Document nodes are unwrapped, so documents and their content can be
+// compared directly. Diff returns an error if a hand-built tree has an
+// alias cycle.
+func Diff(a, b *Node, opts ...DiffOption) (changes []Change, err error) {
+	defer handleErr(&err)
+	d := &differ{}
+	for _, opt := range opts {
+		opt(&d.opts)
+	}
+	d.diff("$", a, b, a, b)
+	return d.changes, nil
+}
+
+// differ accumulates changes while walking two trees.
+type differ struct {
+	opts    diffOptions
+	changes []Change
+}
+
+// diff compares a and b found at path. The original nodes (before alias
+// following) are passed as origA and origB to report their positions.
+func (d *differ) diff(path string, origA, origB, a, b *Node) {
+	a, b = queryRoot(a), queryRoot(b)
+	switch {
+	case a == nil && b == nil:
+		return
+	case a == nil:
+		d.add(ChangeAdded, path, origA, origB, nil, b)
+		return
+	case b == nil:
+		d.add(ChangeRemoved, path, origA, origB, a, nil)
+		return
+	case a.Kind != b.Kind:
+		d.add(ChangeModified, path, origA, origB, a, b)
+		return
+	}
+	switch a.Kind {
+	case ScalarNode:
+		if !scalarsEqual(a, b) {
+			d.add(ChangeModified, path, origA, origB, a, b)
+		}
+	case MappingNode:
+		d.diffMapping(path, origA, origB, a, b)
+	case SequenceNode:
+		d.diffSequence(path, a, b)
+	}
+}
+
+// add records a change.
+func (d *differ) add(kind ChangeKind, path string, origA, origB, from, to *Node) {
+	d.changes = append(d.changes, Change{
+		Kind:     kind,
+		Path:     path,
+		From:     from,
+		To:       to,
+		FromMark: nodeMark(origA),
+		ToMark:   nodeMark(origB),
+	})
+}
+
+// nodeMark returns the source position of a node.
+func nodeMark(n *Node) Mark {
+	if n == nil {
+		return Mark{}
+	}
+	return Mark{Line: n.Line, Column: n.Column}
+}
+
+func (d *differ) diffMapping(path string, origA, origB, a, b *Node) {
+	ea, eb := mappingEntries(a), mappingEntries(b)
+	indexB := make(map[string]int, len(eb)/2)
+	for i := 0; i < len(eb); i += 2 {
+		indexB[fingerprint(eb[i])] = i
+	}
+	seenA := make(map[string]bool, len(ea)/2)
+	var orderA, orderB []string
+	var nested []func()
+	for i := 0; i < len(ea); i += 2 {
+		key := fingerprint(ea[i])
+		seenA[key] = true
+		childPath := appendPathKey(path, ea[i])
+		j, ok := indexB[key]
+		if !ok {
+			va := ea[i+1]
+			nested = append(nested, func() { d.add(ChangeRemoved, childPath, va, b, derefAlias(va), nil) })
+			continue
+		}
+		orderA = append(orderA, key)
+		va, vb := ea[i+1], eb[j+1]
+		nested = append(nested, func() { d.diff(childPath, va, vb, va, vb) })
+	}
+	if d.opts.keyOrder {
+		for i := 0; i < len(eb); i += 2 {
+			if key := fingerprint(eb[i]); seenA[key] {
+				orderB = append(orderB, key)
+			}
+		}
+		if strings.Join(orderA, "\x00") != strings.Join(orderB, "\x00") {
+			d.add(ChangeReordered, path, origA, origB, a, b)
+		}
+	}
+	for _, f := range nested {
+		f()
+	}
+	for i := 0; i < len(eb); i += 2 {
+		if !seenA[fingerprint(eb[i])] {
+			d.add(ChangeAdded, appendPathKey(path, eb[i]), a, eb[i+1], nil, derefAlias(eb[i+1]))
+		}
+	}
+}
+
+// diffSequence aligns the items of two sequences and reports unmatched
+// items. Where items were removed and added at the same place they are
+// compared recursively, so editing one field of a list item reports just
+// that field.
+func (d *differ) diffSequence(path string, a, b *Node) {
+	for _, hunk := range alignSequences(a, b) {
+		for k := 0; k < hunk.paired(); k++ {
+			i, j := hunk.removed[k], hunk.added[k]
+			d.diff(appendPathIndex(path, i), a.Content[i], b.Content[j], a.Content[i], b.Content[j])
+		}
+		for _, i := range hunk.removed[hunk.paired():] {
+			d.add(ChangeRemoved, appendPathIndex(path, i), a.Content[i], b, derefAlias(a.Content[i]), nil)
+		}
+		for _, j := range hunk.added[hunk.paired():] {
+			d.add(ChangeAdded, appendPathIndex(path, j), a, b.Content[j], nil, derefAlias(b.Content[j]))
+		}
+	}
+}
+
+// seqHunk is a run of items that differ between two aligned sequences.
+type seqHunk struct {
+	equal   int   // Number of equal items since the previous hunk
+	removed []int // Indexes of old items not matched in the new sequence
+	added   []int // Indexes of new items not matched in the old sequence
+}
+
+// paired returns the number of removed items that line up with an added
+// item and are best treated as modifications.
+func (h seqHunk) paired() int {
+	if len(h.added) < len(h.removed) {
+		return len(h.added)
+	}
+	return len(h.removed)
+}
+
+// alignSequences aligns the items of two sequences on their longest common
+// subsequence and returns the differing runs in order.
+func alignSequences(a, b *Node) []seqHunk {
+	fa := make([]string, len(a.Content))
+	for i, n := range a.Content {
+		fa[i] = fingerprint(n)
+	}
+	fb := make([]string, len(b.Content))
+	for i, n := range b.Content {
+		fb[i] = fingerprint(n)
+	}
+	// lcs[i][j] is the LCS length of fa[i:] and fb[j:].
+	lcs := make([][]int, len(fa)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(fb)+1)
+	}
+	for i := len(fa) - 1; i >= 0; i-- {
+		for j := len(fb) - 1; j >= 0; j-- {
+			switch {
+			case fa[i] == fb[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var hunks []seqHunk
+	var cur seqHunk
+	i, j := 0, 0
+	for i < len(fa) || j < len(fb) {
+		switch {
+		case i < len(fa) && j < len(fb) && fa[i] == fb[j]:
+			if len(cur.removed) > 0 || len(cur.added) > 0 {
+				hunks = append(hunks, cur)
+				cur = seqHunk{}
+			}
+			cur.equal++
+			i++
+			j++
+		case j >= len(fb) || i < len(fa) && lcs[i+1][j] >= lcs[i][j+1]:
+			cur.removed = append(cur.removed, i)
+			i++
+		default:
+			cur.added = append(cur.added, j)
+			j++
+		}
+	}
+	if len(cur.removed) > 0 || len(cur.added) > 0 {
+		hunks = append(hunks, cur)
+	}
+	return hunks
+}
+
+// --------------------------------------------------------------------------
+// Value comparison
+
+// resolveScalar returns the resolved tag and value of a scalar node.
+// Scalars whose explicit tag does not match their text are reported with
+// the tag and the raw text.
+func resolveScalar(n *Node) (tag string, v any) {
+	if n.indicatedString() {
+		return strTag, n.Value
+	}
+	tag, v, err := tryResolve(n.Tag, n.Value)
+	if err != nil {
+		return shortTag(n.Tag), n.Value
+	}
+	return tag, v
+}
+
+// scalarsEqual reports whether two scalar nodes hold the same data.
+func scalarsEqual(a, b *Node) bool {
+	ta, va := resolveScalar(a)
+	tb, vb := resolveScalar(b)
+	if ta != tb {
+		return false
+	}
+	if ta, ok := va.(time.Time); ok {
+		tb, ok := vb.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+	return valuesEqual(va, vb)
+}
+
+// fingerprint returns a canonical string for the data held by a node, such
+// that two nodes have the same fingerprint exactly when they hold equal
+// data. Mapping keys are ordered, so key order does not matter.
+func fingerprint(n *Node) string {
+	var b strings.Builder
+	writeFingerprint(&b, n, make(map[*Node]bool))
+	return b.String()
+}
+
+func writeFingerprint(b *strings.Builder, n *Node, visiting map[*Node]bool) {
+	n = queryRoot(n)
+	if n == nil {
+		b.WriteString("~")
+		return
+	}
+	if visiting[n] {
+		b.WriteString("<cycle>")
+		return
+	}
+	visiting[n] = true
+	defer delete(visiting, n)
+	switch n.Kind {
+	case ScalarNode:
+		tag, v := resolveScalar(n)
+		b.WriteString(tag)
+		b.WriteByte(' ')
+		switch v := v.(type) {
+		case nil:
+			b.WriteString("null")
+		case string:
+			b.WriteString(strconv.Quote(v))
+		case float64:
+			b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		case time.Time:
+			b.WriteString(v.UTC().Format(time.RFC3339Nano))
+		default:
+			fmt.Fprint(b, v)
+		}
+	case SequenceNode:
+		b.WriteByte('[')
+		for i, item := range n.Content {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeFingerprint(b, item, visiting)
+		}
+		b.WriteByte(']')
+	case MappingNode:
+		entries := mappingEntries(n)
+		pairs := make([]string, 0, len(entries)/2)
+		for i := 0; i+1 < len(entries); i += 2 {
+			var pair strings.Builder
+			writeFingerprint(&pair, entries[i], visiting)
+			pair.WriteByte(':')
+			writeFingerprint(&pair, entries[i+1], visiting)
+			pairs = append(pairs, pair.String())
+		}
+		sort.Strings(pairs)
+		b.WriteByte('{')
+		b.WriteString(strings.Join(pairs, ","))
+		b.WriteByte('}')
+	}
+}
+
+// --------------------------------------------------------------------------
+// Paths
+
+// appendPathKey returns path extended by a mapping key, using the dotted
+// form when the key allows it and the quoted bracket form otherwise.
+func appendPathKey(path string, key *Node) string {
+	key = derefAlias(key)
+	var name string
+	if key != nil && key.Kind == ScalarNode {
+		name = key.Value
+	} else {
+		name = fingerprint(key)
+	}
+	plain := name != ""
+	for i := 0; i < len(name) && plain; i++ {
+		plain = isPathNameChar(name[i])
+	}
+	if plain && !(name[0] == '-' || name[0] >= '0' && name[0] <= '9') {
+		return path + "." + name
+	}
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return path + "['" + r.Replace(name) + "']"
+}
+
+// appendPathIndex returns path extended by a sequence index.
+func appendPathIndex(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// --------------------------------------------------------------------------
+// Rendering
+
+// UnifiedDiff renders changes in a unified-diff style. Each change gets a
+// hunk header naming its kind, path and source positions, followed by the
+// old content prefixed with "-" and the new content prefixed with "+".
+// The names label the old and new trees in the file header.
+func UnifiedDiff(changes []Change, fromName, toName string) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+	for _, c := range changes {
+		fmt.Fprintf(&b, "@@ %s %s (", c.Kind, c.Path)
+		if c.From != nil {
+			fmt.Fprintf(&b, "-%s", c.FromMark.shortString())
+		}
+		if c.To != nil {
+			if c.From != nil {
+				b.WriteByte(' ')
+			}
+			fmt.Fprintf(&b, "+%s", c.ToMark.shortString())
+		}
+		b.WriteString(") @@\n")
+		if c.Kind == ChangeReordered {
+			writePrefixed(&b, "-", mappingKeyList(c.From))
+			writePrefixed(&b, "+", mappingKeyList(c.To))
+			continue
+		}
+		if c.From != nil {
+			writePrefixed(&b, "-", snippet(c.From))
+		}
+		if c.To != nil {
+			writePrefixed(&b, "+", snippet(c.To))
+		}
+	}
+	return b.String()
+}
+
+// writePrefixed writes each line of text with prefix.
+func writePrefixed(b *strings.Builder, prefix, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(prefix)
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+}
+
+// snippet renders a node as YAML without comments for display.
+func snippet(n *Node) string {
+	out, err := Dump(displayCopy(n, make(map[*Node]bool)))
+	if err != nil {
+		return n.Value
+	}
+	return strings.TrimSuffix(string(out), "\n")
+}
+
+// mappingKeyList renders the keys of a mapping as a flow sequence.
+func mappingKeyList(n *Node) string {
+	entries := mappingEntries(n)
+	keys := make([]string, 0, len(entries)/2)
+	for i := 0; i < len(entries); i += 2 {
+		keys = append(keys, snippet(entries[i]))
+	}
+	return "[" + strings.Join(keys, ", ") + "]"
+}
+
+// displayCopy returns a copy of a node tree with aliases expanded and
+// anchors and comments removed, so that any subtree renders on its own.
+func displayCopy(n *Node, visiting map[*Node]bool) *Node {
+	n = derefAlias(n)
+	if n == nil || visiting[n] {
+		return &Node{Kind: ScalarNode, Tag: nullTag, Value: "~"}
+	}
+	visiting[n] = true
+	defer delete(visiting, n)
+	c := &Node{Kind: n.Kind, Style: n.Style, Tag: n.Tag, Value: n.Value}
+	for _, child := range n.Content {
+		c.Content = append(c.Content, displayCopy(child, visiting))
+	}
+	return c
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for structural comparison of Node trees.
+
+package libyaml
+
+import (
+	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+// diffDocs loads two documents and returns their changes as strings.
+func diffDocs(t *testing.T, a, b string, opts ...DiffOption) []string {
+	t.Helper()
+	var na, nb Node
+	assert.NoError(t, Load([]byte(a), &na))
+	assert.NoError(t, Load([]byte(b), &nb))
+	changes, err := Diff(&na, &nb, opts...)
+	assert.NoError(t, err)
+	out := []string{}
+	for _, c := range changes {
+		out = append(out, c.String())
+	}
+	return out
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		opts []DiffOption
+		want []string
+	}{{
+		name: "equal after resolution",
+		a:    "a: 0x10\nb: 'x'\nc: ~\nd: 1.0\ne: 2001-12-14t21:59:43.10-05:00\n",
+		b:    "b: x\na: 16\nc: null\nd: 1.00\ne: 2001-12-15 2:59:43.10\n",
+		want: []string{},
+	}, {
+		name: "type change is a change",
+		a:    "a: 16\nb: true\n",
+		b:    "a: '16'\nb: 'true'\n",
+		want: []string{"changed $.a", "changed $.b"},
+	}, {
+		name: "added removed changed",
+		a:    "keep: 1\nold: 2\nspec: {replicas: 1}\n",
+		b:    "keep: 1\nspec: {replicas: 3}\nnew: 4\n",
+		want: []string{"removed $.old", "changed $.spec.replicas", "added $.new"},
+	}, {
+		name: "kind change",
+		a:    "a: [1]\n",
+		b:    "a: {x: 1}\n",
+		want: []string{"changed $.a"},
+	}, {
+		name: "key order ignored by default",
+		a:    "a: 1\nb: 2\n",
+		b:    "b: 2\na: 1\n",
+		want: []string{},
+	}, {
+		name: "key order reported",
+		a:    "m: {a: 1, b: 2}\n",
+		b:    "m: {b: 2, a: 1, c: 3}\n",
+		opts: []DiffOption{DiffKeyOrder()},
+		want: []string{"reordered $.m", "added $.m.c"},
+	}, {
+		name: "sequence insert and delete",
+		a:    "[a, b, c, d]\n",
+		b:    "[x, a, c, d, e]\n",
+		want: []string{"added $[0]", "removed $[1]", "added $[4]"},
+	}, {
+		name: "sequence item edited in place",
+		a:    "- {name: web, image: nginx:1}\n- {name: db, image: pg}\n",
+		b:    "- {name: web, image: nginx:2}\n- {name: db, image: pg}\n",
+		want: []string{"changed $[0].image"},
+	}, {
+		name: "merge keys and aliases",
+		a:    "base: &b {x: 1, y: 2}\nuse: {<<: *b, y: 3}\n",
+		b:    "base: {x: 1, y: 2}\nuse: {x: 1, y: 3}\n",
+		want: []string{},
+	}, {
+		name: "keys needing quotes",
+		a:    "'a.b': 1\n'1': 2\n",
+		b:    "'a.b': 2\n'1': 3\n",
+		want: []string{"changed $['a.b']", "changed $['1']"},
+	}, {
+		name: "explicit tag not matching the text",
+		a:    "a: !!int foo\nb: !!int x\n",
+		b:    "a: !!int foo\nb: !!int y\n",
+		want: []string{"changed $.b"},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.DeepEqual(t, tc.want, diffDocs(t, tc.a, tc.b, tc.opts...))
+		})
+	}
+}
+
+func TestDiffPathsAndMarks(t *testing.T) {
+	var a, b Node
+	assert.NoError(t, Load([]byte("spec:\n  x.y: 1\n  list: [1, 2]\n"), &a))
+	assert.NoError(t, Load([]byte("spec:\n  x.y: 2\n  list: [1, 2, 3]\n"), &b))
+	changes, err := Diff(&a, &b)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(changes))
+
+	c := changes[0]
+	assert.Equal(t, ChangeModified, c.Kind)
+	assert.Equal(t, "$.spec['x.y']", c.Path)
+	assert.Equal(t, Mark{Line: 2, Column: 8}, c.FromMark)
+	assert.Equal(t, Mark{Line: 2, Column: 8}, c.ToMark)
+	found, err := a.Query(c.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, c.From, found[0])
+
+	c = changes[1]
+	assert.Equal(t, ChangeAdded, c.Kind)
+	assert.Equal(t, "$.spec.list[2]", c.Path)
+	assert.IsNil(t, c.From)
+	assert.Equal(t, "3", c.To.Value)
+	assert.Equal(t, Mark{Line: 3, Column: 9}, c.FromMark)
+	assert.Equal(t, Mark{Line: 3, Column: 16}, c.ToMark)
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	var a, b Node
+	assert.NoError(t, Load([]byte("name: web\nports: [80]\nold:\n  x: 1\n"), &a))
+	assert.NoError(t, Load([]byte("name: api\nports: [80, 443]\n"), &b))
+	changes, err := Diff(&a, &b)
+	assert.NoError(t, err)
+	got := UnifiedDiff(changes, "a.yaml", "b.yaml")
+	want := `--- a.yaml
++++ b.yaml
+@@ changed $.name (-L1.C7 +L1.C7) @@
+-web
++api
+@@ added $.ports[1] (+L2.C13) @@
++443
+@@ removed $.old (-L4.C3) @@
+-x: 1
+`
+	assert.Equal(t, want, got)
+	assert.Equal(t, "", UnifiedDiff(nil, "a", "b"))
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Discriminated interface values.
+//
+// WithDiscriminator registers the concrete types of an interface type by
+// name. When loading into a value of the interface type, the Constructor
+// selects the type from a local tag such as !http, or from a discriminator
+// key in the mapping such as type: http, and constructs that type. The
+// Representer writes the key, or the tag, back when dumping.
+
+package libyaml
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// discriminator holds the concrete types registered for an interface type.
+type discriminator struct {
+	iface  reflect.Type
+	key    string
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}
+
+// newDiscriminator returns the discriminator for the interface type T.
+func newDiscriminator[T any](key string, types map[string]T) (*discriminator, error) {
+	iface := reflect.TypeOf((*T)(nil)).Elem()
+	if iface.Kind() != reflect.Interface {
+		return nil, fmt.Errorf("yaml: WithDiscriminator needs an interface type, not %s", iface)
+	}
+	d := &discriminator{
+		iface:  iface,
+		key:    key,
+		byName: make(map[string]reflect.Type, len(types)),
+		byType: make(map[reflect.Type]string, len(types)),
+	}
+	for name, v := range types {
+		t := reflect.TypeOf(v)
+		if t == nil {
+			return nil, fmt.Errorf("yaml: WithDiscriminator type %q of %s is nil", name, iface)
+		}
+		if other, ok := d.byType[t]; ok {
+			return nil, fmt.Errorf("yaml: WithDiscriminator type %s of %s is registered as both %q and %q", t, iface, other, name)
+		}
+		d.byName[name] = t
+		d.byType[t] = name
+	}
+	return d, nil
+}
+
+// discriminated constructs n into out, an interface with a discriminator,
+// and reports whether it selected a type for n. Nodes without a tag or key
+// naming a type are constructed as usual.
+func (c *Constructor) discriminated(d *discriminator, n *Node, out reflect.Value) (handled, good bool) {
+	var t reflect.Type
+	tag := n.ShortTag()
+	if !isCoreTag(tag) && tag[0] == '!' {
+		t = d.byName[tag[1:]]
+	}
+	keyIndex := -1
+	if t == nil && d.key != "" && n.Kind == MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if k := n.Content[i]; k.Kind == ScalarNode && k.Value == d.key {
+				keyIndex = i
+				break
+			}
+		}
+		if keyIndex < 0 {
+			c.TypeErrors = append(c.TypeErrors, formatConstructorError(
+				fmt.Errorf("cannot construct %s without a %q key", d.iface, d.key),
+				Mark{Line: n.Line, Column: n.Column},
+			))
+			return true, false
+		}
+		v := n.Content[keyIndex+1]
+		if t = d.byName[v.Value]; t == nil || v.Kind != ScalarNode {
+			c.TypeErrors = append(c.TypeErrors, formatConstructorError(
+				fmt.Errorf("unknown %s %q for %s", d.key, v.Value, d.iface),
+				Mark{Line: v.Line, Column: v.Column},
+			))
+			return true, false
+		}
+	}
+	if t == nil {
+		return false, false
+	}
+
+	// Construct a copy of the node without the tag, and without the key
+	// unless the type has a field for it.
+	m := *n
+	m.Tag = ""
+	if keyIndex >= 0 && !c.hasKey(t, d.key) {
+		m.Content = make([]*Node, 0, len(n.Content)-2)
+		m.Content = append(m.Content, n.Content[:keyIndex]...)
+		m.Content = append(m.Content, n.Content[keyIndex+2:]...)
+	}
+	v := reflect.New(t).Elem()
+	good = c.Construct(&m, v)
+	out.Set(v)
+	return true, good
+}
+
+// hasKey reports whether values of type t keep the given mapping key: they
+// are not structs, or structs with a field or inline map for it.
+func (c *Constructor) hasKey(t reflect.Type, key string) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+	sinfo, err := getStructInfo(t, c.structOpts)
+	if err != nil {
+		panic(err)
+	}
+	_, _, ok := sinfo.field(key, c.caseInsensitiveKeys)
+	return ok || sinfo.InlineMap != -1
+}
+
+// discriminated represents in, the value held by an interface with a
+// discriminator, with the key or tag naming its type.
+func (r *Representer) discriminated(d *discriminator, in reflect.Value) *Node {
+	name, ok := d.byType[in.Type()]
+	if !ok {
+		failDumpf(RepresenterStage, "cannot represent %s as %s: type is not registered", in.Type(), d.iface)
+	}
+	node := r.represent("", in)
+	if d.key == "" || node.Kind != MappingNode {
+		node.Tag = "!" + name
+		return node
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == d.key {
+			node.Content[i+1] = &Node{Kind: ScalarNode, Tag: strTag, Value: name}
+			return node
+		}
+	}
+	node.Content = append([]*Node{
+		{Kind: ScalarNode, Tag: strTag, Value: d.key},
+		{Kind: ScalarNode, Tag: strTag, Value: name},
+	}, node.Content...)
+	return node
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for discriminated interface values.
+
+package libyaml
+
+import (
+	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+type backend interface{ kind() string }
+
+type httpBackend struct {
+	URL     string `yaml:"url"`
+	Timeout int    `yaml:"timeout,omitempty"`
+}
+
+type grpcBackend struct {
+	Target string `yaml:"target"`
+}
+
+type execBackend struct {
+	Type    string   `yaml:"type"`
+	Command []string `yaml:"command,flow"`
+}
+
+func (httpBackend) kind() string  { return "http" }
+func (*grpcBackend) kind() string { return "grpc" }
+func (execBackend) kind() string  { return "exec" }
+
+type backendConfig struct {
+	Default  backend            `yaml:"default"`
+	Backends []backend          `yaml:"backends"`
+	ByName   map[string]backend `yaml:"by-name,omitempty"`
+}
+
+var withBackends = WithDiscriminator("type", map[string]backend{
+	"http": httpBackend{},
+	"grpc": &grpcBackend{},
+	"exec": execBackend{},
+})
+
+func TestDiscriminatorLoad(t *testing.T) {
+	src := "default: {type: http, url: 'http://a'}\n" +
+		"backends:\n" +
+		"- type: grpc\n  target: b:443\n" +
+		"- type: exec\n  command: [ls, -l]\n" +
+		"- !http {url: 'http://c', timeout: 5}\n" +
+		"- null\n" +
+		"by-name:\n  d: {type: grpc, target: d}\n"
+	var cfg backendConfig
+	assert.NoError(t, Load([]byte(src), &cfg, withBackends, WithKnownFields()))
+	assert.Equal(t, backend(httpBackend{URL: "http://a"}), cfg.Default)
+	assert.DeepEqual(t, []backend{
+		&grpcBackend{Target: "b:443"},
+		execBackend{Type: "exec", Command: []string{"ls", "-l"}},
+		httpBackend{URL: "http://c", Timeout: 5},
+		nil,
+	}, cfg.Backends)
+	assert.DeepEqual(t, map[string]backend{"d": &grpcBackend{Target: "d"}}, cfg.ByName)
+}
+
+func TestDiscriminatorDump(t *testing.T) {
+	cfg := backendConfig{
+		Default: httpBackend{URL: "http://a"},
+		Backends: []backend{
+			&grpcBackend{Target: "b:443"},
+			execBackend{Command: []string{"ls"}},
+		},
+	}
+	out, err := Dump(&cfg, withBackends)
+	assert.NoError(t, err)
+	want := "default:\n  type: http\n  url: http://a\n" +
+		"backends:\n" +
+		"- type: grpc\n  target: b:443\n" +
+		"- type: exec\n  command: [ls]\n"
+	assert.Equal(t, want, string(out))
+
+	var again backendConfig
+	assert.NoError(t, Load(out, &again, withBackends))
+	assert.Equal(t, cfg.Default, again.Default)
+	assert.DeepEqual(t, []backend{
+		&grpcBackend{Target: "b:443"},
+		execBackend{Type: "exec", Command: []string{"ls"}},
+	}, again.Backends)
+
+	// Without the option, values are dumped as usual.
+	out, err = Dump(&backendConfig{Default: httpBackend{URL: "x"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "default:\n  url: x\nbackends: []\n", string(out))
+}
+
+func TestDiscriminatorTags(t *testing.T) {
+	withTags := WithDiscriminator("", map[string]backend{
+		"http": httpBackend{},
+		"grpc": &grpcBackend{},
+	})
+	src := "default: !grpc {target: a}\nbackends: [!http {url: b}]\n"
+	var cfg backendConfig
+	assert.NoError(t, Load([]byte(src), &cfg, withTags))
+	assert.DeepEqual(t, backend(&grpcBackend{Target: "a"}), cfg.Default)
+	assert.DeepEqual(t, []backend{httpBackend{URL: "b"}}, cfg.Backends)
+
+	out, err := Dump(&cfg, withTags)
+	assert.NoError(t, err)
+	assert.Equal(t, "default: !grpc\n  target: a\nbackends:\n- !http\n  url: b\n", string(out))
+}
+
+func TestDiscriminatorErrors(t *testing.T) {
+	var cfg backendConfig
+	err := Load([]byte("default: {url: x}\n"), &cfg, withBackends)
+	assert.ErrorMatches(t, `yaml: construct errors: line 1: cannot construct libyaml.backend without a "type" key`, err)
+
+	err = Load([]byte("default: {type: ftp}\n"), &cfg, withBackends)
+	assert.ErrorMatches(t, `yaml: construct errors: line 1: unknown type "ftp" for libyaml.backend`, err)
+
+	err = Load([]byte("default: {type: http, url: [x]}\n"), &cfg, withBackends)
+	assert.ErrorMatches(t, "yaml: construct errors: line 1: cannot construct !!seq into string", err)
+
+	_, err = Dump(&backendConfig{Default: &execBackend{}}, withBackends)
+	assert.ErrorMatches(t, `.*cannot represent \*libyaml.execBackend as libyaml.backend: type is not registered`, err)
+
+	err = Load([]byte("a: 1"), &cfg, WithDiscriminator("type", map[string]httpBackend{}))
+	assert.ErrorMatches(t, "yaml: WithDiscriminator needs an interface type, not libyaml.httpBackend", err)
+
+	err = Load([]byte("a: 1"), &cfg, WithDiscriminator("type", map[string]backend{"x": nil}))
+	assert.ErrorMatches(t, `yaml: WithDiscriminator type "x" of libyaml.backend is nil`, err)
+}
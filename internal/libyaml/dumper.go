@@ -114,6 +114,7 @@ func (d *Dumper) Dump(v any) (err error) {
 
 	// Stage 1: Represent - Go values → Tagged Node tree
 	node := d.representer.Represent("", reflect.ValueOf(v))
+	node = transformDumped(d.options, node)
 
 	// Stage 2: Desolve - Remove inferable tags
 	d.desolver.Desolve(node)
@@ -0,0 +1,686 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// In-place editing of Node trees.
+//
+// The methods here mutate MappingNode and SequenceNode content while keeping
+// comments attached to the entries they describe:
+//
+//   - A replaced value inherits the comments of the value it replaces, and
+//     its scalar style when the resolved tag is unchanged.
+//   - A removed entry takes its head and line comments with it, but its foot
+//     comment is handed to the previous entry (or to the next entry's head
+//     when it was first), since foot comments usually describe what follows.
+//   - An entry appended after the last one takes over the trailing foot
+//     comment, so comments closing a block stay at the end of that block.
+//
+// In a mapping, comments on an entry live on its key node; in a sequence,
+// on the item node.
+
+package libyaml
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned by the editing methods when a key, index or path
+// does not match anything in the node.
+var ErrNotFound = errors.New("yaml: not found")
+
+// --------------------------------------------------------------------------
+// Mapping edits
+
+// SetKey sets the value for key in a mapping node. If the key exists its
+// value is replaced in place, otherwise a new entry is appended.
+// Only entries defined directly in the mapping are considered; keys merged
+// in via << are shadowed by the new entry.
+func (n *Node) SetKey(key string, value *Node) (err error) {
+	defer handleErr(&err)
+	if err := n.checkKind(MappingNode, "set key"); err != nil {
+		return err
+	}
+	if value == nil {
+		return errors.New("yaml: cannot set key to a nil node")
+	}
+	if i := n.keyIndex(key); i >= 0 {
+		inheritDecorations(value, n.Content[i+1])
+		n.Content[i+1] = value
+		return nil
+	}
+	n.insertPair(len(n.Content)/2, newKeyNode(key), value)
+	return nil
+}
+
+// InsertKey inserts a new entry at position index among the mapping's
+// entries, where 0 is first and the entry count appends.
+// It fails if the key already exists.
+func (n *Node) InsertKey(index int, key string, value *Node) (err error) {
+	defer handleErr(&err)
+	if err := n.checkKind(MappingNode, "insert key"); err != nil {
+		return err
+	}
+	if value == nil {
+		return errors.New("yaml: cannot insert a nil node")
+	}
+	if n.keyIndex(key) >= 0 {
+		return fmt.Errorf("yaml: cannot insert key %q: key already exists", key)
+	}
+	pos, err := insertPosition(index, len(n.Content)/2)
+	if err != nil {
+		return err
+	}
+	n.insertPair(pos, newKeyNode(key), value)
+	return nil
+}
+
+// DeleteKey removes the entry for key from a mapping node.
+// It returns an error wrapping [ErrNotFound] if the key is not present.
+func (n *Node) DeleteKey(key string) (err error) {
+	defer handleErr(&err)
+	if err := n.checkKind(MappingNode, "delete key"); err != nil {
+		return err
+	}
+	i := n.keyIndex(key)
+	if i < 0 {
+		return fmt.Errorf("%w: key %q", ErrNotFound, key)
+	}
+	n.removePair(i / 2)
+	return nil
+}
+
+// MoveKey moves the entry for key to position index among the mapping's
+// entries, carrying its comments along.
+func (n *Node) MoveKey(key string, index int) (err error) {
+	defer handleErr(&err)
+	if err := n.checkKind(MappingNode, "move key"); err != nil {
+		return err
+	}
+	i := n.keyIndex(key)
+	if i < 0 {
+		return fmt.Errorf("%w: key %q", ErrNotFound, key)
+	}
+	count := len(n.Content) / 2
+	if index < 0 {
+		index += count
+	}
+	if index < 0 || index >= count {
+		return fmt.Errorf("yaml: index %d out of range for mapping with %d entries", index, count)
+	}
+	k, v := n.removePair(i / 2)
+	n.insertPair(index, k, v)
+	return nil
+}
+
+// keyIndex returns the index in Content of the scalar key node matching key,
+// or -1 if there is none.
+func (n *Node) keyIndex(key string) int {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k := n.Content[i]
+		if k.Kind == ScalarNode && k.Value == key && !isMergeKey(k) {
+			return i
+		}
+	}
+	return -1
+}
+
+// newKeyNode returns a string key node for key.
+func newKeyNode(key string) *Node {
+	return &Node{Kind: ScalarNode, Tag: strTag, Value: key}
+}
+
+// insertPair inserts a key/value entry before entry pos.
+func (n *Node) insertPair(pos int, key, value *Node) {
+	count := len(n.Content) / 2
+	if pos == count && count > 0 {
+		moveTrailingComment(n.Content[len(n.Content)-2], key)
+	}
+	n.Content = append(n.Content, nil, nil)
+	copy(n.Content[2*pos+2:], n.Content[2*pos:])
+	n.Content[2*pos] = key
+	n.Content[2*pos+1] = value
+}
+
+// removePair removes entry pos and returns its key and value nodes.
+func (n *Node) removePair(pos int) (key, value *Node) {
+	key, value = n.Content[2*pos], n.Content[2*pos+1]
+	var prev, next *Node
+	if pos > 0 {
+		prev = n.Content[2*pos-2]
+	}
+	if 2*pos+2 < len(n.Content) {
+		next = n.Content[2*pos+2]
+	}
+	handOffFootComment(key, prev, next)
+	n.Content = append(n.Content[:2*pos], n.Content[2*pos+2:]...)
+	return key, value
+}
+
+// --------------------------------------------------------------------------
+// Sequence edits
+
+// SetIndex replaces the item at index in a sequence node. A negative index
+// counts from the end.
+func (n *Node) SetIndex(index int, value *Node) (err error) {
+	defer handleErr(&err)
+	if err := n.checkKind(SequenceNode, "set index"); err != nil {
+		return err
+	}
+	if value == nil {
+		return errors.New("yaml: cannot set index to a nil node")
+	}
+	i, err := n.itemIndex(index)
+	if err != nil {
+		return err
+	}
+	inheritDecorations(value, n.Content[i])
+	n.Content[i] = value
+	return nil
+}
+
+// InsertIndex inserts value before the item at index in a sequence node.
+// An index equal to the item count appends; a negative index counts from
+// the end.
+func (n *Node) InsertIndex(index int, value *Node) (err error) {
+	defer handleErr(&err)
+	if err := n.checkKind(SequenceNode, "insert index"); err != nil {
+		return err
+	}
+	if value == nil {
+		return errors.New("yaml: cannot insert a nil node")
+	}
+	pos, err := insertPosition(index, len(n.Content))
+	if err != nil {
+		return err
+	}
+	n.insertItem(pos, value)
+	return nil
+}
+
+// DeleteIndex removes the item at index from a sequence node. A negative
+// index counts from the end.
+func (n *Node) DeleteIndex(index int) (err error) {
+	defer handleErr(&err)
+	if err := n.checkKind(SequenceNode, "delete index"); err != nil {
+		return err
+	}
+	i, err := n.itemIndex(index)
+	if err != nil {
+		return err
+	}
+	n.removeItem(i)
+	return nil
+}
+
+// MoveIndex moves the item at from so that it ends up at index to,
+// carrying its comments along.
+func (n *Node) MoveIndex(from, to int) (err error) {
+	defer handleErr(&err)
+	if err := n.checkKind(SequenceNode, "move index"); err != nil {
+		return err
+	}
+	i, err := n.itemIndex(from)
+	if err != nil {
+		return err
+	}
+	j, err := n.itemIndex(to)
+	if err != nil {
+		return err
+	}
+	n.insertItem(j, n.removeItem(i))
+	return nil
+}
+
+// itemIndex normalizes index for a sequence node and checks its range.
+func (n *Node) itemIndex(index int) (int, error) {
+	i := index
+	if i < 0 {
+		i += len(n.Content)
+	}
+	if i < 0 || i >= len(n.Content) {
+		return 0, fmt.Errorf("%w: index %d out of range for sequence with %d items", ErrNotFound, index, len(n.Content))
+	}
+	return i, nil
+}
+
+// insertItem inserts value before item pos.
+func (n *Node) insertItem(pos int, value *Node) {
+	if pos == len(n.Content) && pos > 0 {
+		moveTrailingComment(n.Content[pos-1], value)
+	}
+	n.Content = append(n.Content, nil)
+	copy(n.Content[pos+1:], n.Content[pos:])
+	n.Content[pos] = value
+}
+
+// removeItem removes item pos and returns it.
+func (n *Node) removeItem(pos int) *Node {
+	item := n.Content[pos]
+	var prev, next *Node
+	if pos > 0 {
+		prev = n.Content[pos-1]
+	}
+	if pos+1 < len(n.Content) {
+		next = n.Content[pos+1]
+	}
+	handOffFootComment(item, prev, next)
+	n.Content = append(n.Content[:pos], n.Content[pos+1:]...)
+	return item
+}
+
+// --------------------------------------------------------------------------
+// Path edits
+
+// SetPath sets the node addressed by a path expression. The last segment
+// of the path must name a single key or index; every node matched by the
+// rest of the path gets the value (each additional match gets a copy).
+// Missing intermediate mappings are created when the path consists only of
+// plain keys.
+func (n *Node) SetPath(expr string, value *Node) (err error) {
+	defer handleErr(&err)
+	return n.editPath(expr, value, func(parent *Node, last pathSelector, value *Node) error {
+		switch sel := last.(type) {
+		case keySelector:
+			return parent.SetKey(sel.keys[0], value)
+		case indexSelector:
+			return parent.SetIndex(sel.indexes[0], value)
+		}
+		panic("unreachable")
+	})
+}
+
+// InsertPath inserts a node at the location addressed by a path expression.
+// If the last segment is an index the value is inserted before that item
+// (an index equal to the item count appends). If it is a key, a new entry
+// is appended to the mapping and inserting an existing key fails.
+func (n *Node) InsertPath(expr string, value *Node) (err error) {
+	defer handleErr(&err)
+	return n.editPath(expr, value, func(parent *Node, last pathSelector, value *Node) error {
+		switch sel := last.(type) {
+		case keySelector:
+			if parent.Kind == MappingNode {
+				return parent.InsertKey(len(parent.Content)/2, sel.keys[0], value)
+			}
+			return parent.checkKind(MappingNode, "insert key")
+		case indexSelector:
+			return parent.InsertIndex(sel.indexes[0], value)
+		}
+		panic("unreachable")
+	})
+}
+
+// DeletePath removes every node matched by a path expression from its
+// parent collection. Nodes reached only through aliases or << merge keys
+// belong to another collection and are not removed.
+// It returns an error wrapping [ErrNotFound] if nothing was removed.
+func (n *Node) DeletePath(expr string) (err error) {
+	defer handleErr(&err)
+	p, err := CompilePath(expr)
+	if err != nil {
+		return err
+	}
+	if len(p.segments) == 0 {
+		return fmt.Errorf("yaml: cannot delete the root node")
+	}
+	root := queryRoot(n)
+	if root == nil {
+		return fmt.Errorf("%w: %s", ErrNotFound, expr)
+	}
+	ctx := &pathContext{root: root}
+	prefix, last := p.segments[:len(p.segments)-1], p.segments[len(p.segments)-1]
+	parents := ctx.eval(prefix, root)
+	if last.recursive {
+		var all []*Node
+		for _, parent := range parents {
+			all = append(all, descendants(parent)...)
+		}
+		parents = all
+	}
+	removed := 0
+	for _, parent := range parents {
+		targets := make(map[*Node]bool)
+		for _, c := range last.sel.selectFrom(ctx, parent, nil) {
+			targets[c] = true
+		}
+		switch parent.Kind {
+		case MappingNode:
+			for i := len(parent.Content) - 2; i >= 0; i -= 2 {
+				if targets[parent.Content[i+1]] && !isMergeKey(parent.Content[i]) {
+					parent.removePair(i / 2)
+					removed++
+				}
+			}
+		case SequenceNode:
+			for i := len(parent.Content) - 1; i >= 0; i-- {
+				if targets[parent.Content[i]] {
+					parent.removeItem(i)
+					removed++
+				}
+			}
+		}
+	}
+	if removed == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, expr)
+	}
+	return nil
+}
+
+// MovePath moves the single node matched by from to the location addressed
+// by to, as if by DeletePath followed by SetPath for a key or InsertPath
+// for an index. The node keeps its own comments.
+// If the node cannot be placed at to, the tree is left unchanged.
+func (n *Node) MovePath(from, to string) (err error) {
+	defer handleErr(&err)
+	nodes, err := n.Query(from)
+	if err != nil {
+		return err
+	}
+	if len(nodes) != 1 {
+		if len(nodes) == 0 {
+			return fmt.Errorf("%w: %s", ErrNotFound, from)
+		}
+		return fmt.Errorf("yaml: cannot move %s: path matches %d nodes", from, len(nodes))
+	}
+	toPath, err := CompilePath(to)
+	if err != nil {
+		return err
+	}
+	if _, err := editTarget(toPath); err != nil {
+		return err
+	}
+	value := nodes[0]
+	saved := snapshotTree(n)
+	if err := n.DeletePath(from); err != nil {
+		return err
+	}
+	if _, ok := toPath.segments[len(toPath.segments)-1].sel.(indexSelector); ok {
+		err = n.InsertPath(to, value)
+	} else {
+		err = n.SetPath(to, value)
+	}
+	if err != nil {
+		saved.restore()
+	}
+	return err
+}
+
+// treeSnapshot holds shallow copies of the nodes of a tree, with their own
+// Content slices, so that a failed sequence of edits can be undone in place.
+type treeSnapshot map[*Node]Node
+
+// snapshotTree records the nodes of the tree rooted at n. Aliases are not
+// followed, since their targets are recorded where they are anchored.
+func snapshotTree(n *Node) treeSnapshot {
+	s := make(treeSnapshot)
+	var record func(n *Node)
+	record = func(n *Node) {
+		if n == nil {
+			return
+		}
+		if _, ok := s[n]; ok {
+			return
+		}
+		c := *n
+		c.Content = append([]*Node(nil), n.Content...)
+		s[n] = c
+		for _, child := range n.Content {
+			record(child)
+		}
+	}
+	record(n)
+	return s
+}
+
+// restore puts every recorded node back as it was. Nodes added since the
+// snapshot are dropped along with the content that held them.
+func (s treeSnapshot) restore() {
+	for n, c := range s {
+		*n = c
+	}
+}
+
+// editTarget returns the final selector of a path used for editing, which
+// must select a single key or index.
+func editTarget(p *Path) (pathSelector, error) {
+	if len(p.segments) == 0 {
+		return nil, fmt.Errorf("yaml: path %q does not name a key or index", p.expr)
+	}
+	last := p.segments[len(p.segments)-1]
+	if !last.recursive {
+		switch sel := last.sel.(type) {
+		case keySelector:
+			if len(sel.keys) == 1 {
+				return sel, nil
+			}
+		case indexSelector:
+			if len(sel.indexes) == 1 {
+				return sel, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("yaml: path %q must end in a single key or index", p.expr)
+}
+
+// editPath resolves the parents addressed by expr and applies edit to each
+// of them with the path's final selector.
+func (n *Node) editPath(expr string, value *Node, edit func(parent *Node, last pathSelector, value *Node) error) error {
+	if value == nil {
+		return errors.New("yaml: cannot set a path to a nil node")
+	}
+	p, err := CompilePath(expr)
+	if err != nil {
+		return err
+	}
+	last, err := editTarget(p)
+	if err != nil {
+		return err
+	}
+	root := queryRoot(n)
+	if root == nil {
+		return fmt.Errorf("%w: %s", ErrNotFound, expr)
+	}
+	prefix := p.segments[:len(p.segments)-1]
+	parents := (&pathContext{root: root}).eval(prefix, root)
+	if len(parents) == 0 {
+		parent, ok := createPath(root, prefix)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrNotFound, expr)
+		}
+		parents = []*Node{parent}
+	}
+	for i, parent := range parents {
+		v := value
+		if i > 0 {
+			v = cloneNode(value)
+		}
+		if err := edit(parent, last, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createPath walks segments from root, creating empty mappings for missing
+// keys. It only handles paths made of single plain keys.
+func createPath(root *Node, segments []pathSegment) (*Node, bool) {
+	current := root
+	for _, seg := range segments {
+		sel, ok := seg.sel.(keySelector)
+		if !ok || seg.recursive || len(sel.keys) != 1 || current.Kind != MappingNode {
+			return nil, false
+		}
+		if i := current.keyIndex(sel.keys[0]); i >= 0 {
+			current = derefAlias(current.Content[i+1])
+			continue
+		}
+		child := &Node{Kind: MappingNode, Tag: mapTag}
+		current.insertPair(len(current.Content)/2, newKeyNode(sel.keys[0]), child)
+		current = child
+	}
+	return current, current.Kind == MappingNode || current.Kind == SequenceNode
+}
+
+// --------------------------------------------------------------------------
+// Helpers
+
+// checkKind returns an error if the node is not of the wanted kind.
+func (n *Node) checkKind(want Kind, op string) error {
+	if n.Kind != want {
+		return fmt.Errorf("yaml: cannot %s on %s node", op, kindName(n.Kind))
+	}
+	return nil
+}
+
+// kindName returns a readable name for a node kind.
+func kindName(k Kind) string {
+	switch k {
+	case DocumentNode:
+		return "document"
+	case SequenceNode:
+		return "sequence"
+	case MappingNode:
+		return "mapping"
+	case ScalarNode:
+		return "scalar"
+	case AliasNode:
+		return "alias"
+	case StreamNode:
+		return "stream"
+	}
+	return "empty"
+}
+
+// insertPosition normalizes an insertion index for a collection with count
+// entries, where count itself is a valid position.
+func insertPosition(index, count int) (int, error) {
+	pos := index
+	if pos < 0 {
+		pos += count
+	}
+	if pos < 0 || pos > count {
+		return 0, fmt.Errorf("yaml: insert position %d out of range for %d entries", index, count)
+	}
+	return pos, nil
+}
+
+// inheritDecorations copies comments, and style where it does not change
+// meaning, from the node being replaced onto its replacement. Comments that
+// are already set on the replacement are kept.
+func inheritDecorations(value, old *Node) {
+	if value.HeadComment == "" && value.LineComment == "" && value.FootComment == "" {
+		value.HeadComment = old.HeadComment
+		value.LineComment = old.LineComment
+		value.FootComment = old.FootComment
+	}
+	if value.Style != 0 || value.Kind != old.Kind {
+		return
+	}
+	switch value.Kind {
+	case ScalarNode:
+		if resolvedTag(value) == resolvedTag(old) {
+			value.Style = old.Style &^ TaggedStyle
+		}
+	case MappingNode, SequenceNode:
+		value.Style = old.Style & FlowStyle
+	}
+}
+
+// resolvedTag returns the short tag a scalar node stands for, resolving
+// untagged plain scalars the way the loader would.
+func resolvedTag(n *Node) string {
+	if n.Tag == "" && n.Style&(SingleQuotedStyle|DoubleQuotedStyle|LiteralStyle|FoldedStyle) == 0 {
+		tag, _ := resolve("", n.Value)
+		return tag
+	}
+	return n.ShortTag()
+}
+
+// handOffFootComment passes the foot comment of a node being removed to
+// prev, or to the head of next if there is no previous entry.
+func handOffFootComment(removed, prev, next *Node) {
+	if removed.FootComment == "" {
+		return
+	}
+	switch {
+	case prev != nil:
+		prev.FootComment = joinComments(prev.FootComment, removed.FootComment)
+	case next != nil:
+		next.HeadComment = joinComments(removed.FootComment, next.HeadComment)
+	}
+	removed.FootComment = ""
+}
+
+// moveTrailingComment moves the foot comment closing a collection from its
+// old last entry to a new last entry.
+func moveTrailingComment(oldLast, newLast *Node) {
+	if oldLast.FootComment == "" {
+		return
+	}
+	newLast.FootComment = joinComments(newLast.FootComment, oldLast.FootComment)
+	oldLast.FootComment = ""
+}
+
+// joinComments concatenates two comment blocks.
+func joinComments(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return a + "\n" + b
+}
+
+// cloneNode returns a deep copy of n. Aliases to nodes inside n point to
+// their copies; aliases to nodes outside n are shared.
+func cloneNode(n *Node) *Node {
+	if n == nil {
+		return nil
+	}
+	copies := make(map[*Node]*Node)
+	c := cloneInto(n, copies)
+	for _, cn := range copies {
+		if cn.Kind == AliasNode {
+			if target, ok := copies[cn.Alias]; ok {
+				cn.Alias = target
+			}
+		}
+	}
+	return c
+}
+
+func cloneInto(n *Node, copies map[*Node]*Node) *Node {
+	c := *n
+	copies[n] = &c
+	if n.Content != nil {
+		c.Content = make([]*Node, len(n.Content))
+		for i, child := range n.Content {
+			c.Content[i] = cloneInto(child, copies)
+		}
+	}
+	return &c
+}
+
+// detachedCopy returns a deep copy of n with aliases expanded and anchors
+// removed, so that it can be placed anywhere in another tree.
+func detachedCopy(n *Node) *Node {
+	return expandCopy(n, make(map[*Node]bool))
+}
+
+func expandCopy(n *Node, visiting map[*Node]bool) *Node {
+	n = derefAlias(n)
+	if n == nil || visiting[n] {
+		return &Node{Kind: ScalarNode, Tag: nullTag, Value: "null"}
+	}
+	visiting[n] = true
+	defer delete(visiting, n)
+	c := *n
+	c.Anchor = ""
+	if n.Content != nil {
+		c.Content = make([]*Node, len(n.Content))
+		for i, child := range n.Content {
+			c.Content[i] = expandCopy(child, visiting)
+		}
+	}
+	return &c
+}
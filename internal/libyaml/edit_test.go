@@ -0,0 +1,211 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for in-place Node editing.
+
+package libyaml
+
+import (
+	"errors"
+	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+// editDoc loads src, applies edit to the document node and returns the
+// re-dumped YAML.
+func editDoc(t *testing.T, src string, edit func(root *Node) error) string {
+	t.Helper()
+	var doc Node
+	assert.NoError(t, Load([]byte(src), &doc))
+	assert.NoError(t, edit(&doc))
+	out, err := Dump(&doc)
+	assert.NoError(t, err)
+	return string(out)
+}
+
+// scalar returns an untagged plain scalar node.
+func scalar(value string) *Node {
+	return &Node{Kind: ScalarNode, Value: value}
+}
+
+func TestEditMapping(t *testing.T) {
+	const src = `# head a
+a: 1 # line a
+# head b
+b: "two" # line b
+c: 3
+# closing
+`
+	tests := []struct {
+		name string
+		edit func(m *Node) error
+		want string
+	}{{
+		name: "set existing keeps comments and style",
+		edit: func(m *Node) error { return m.SetKey("b", scalar("deux")) },
+		want: "# head a\na: 1 # line a\n# head b\nb: \"deux\" # line b\nc: 3\n# closing\n",
+	}, {
+		name: "set existing with different type drops quoting",
+		edit: func(m *Node) error { return m.SetKey("b", scalar("2")) },
+		want: "# head a\na: 1 # line a\n# head b\nb: 2 # line b\nc: 3\n# closing\n",
+	}, {
+		name: "set new key appends before closing comment",
+		edit: func(m *Node) error { return m.SetKey("d", scalar("4")) },
+		want: "# head a\na: 1 # line a\n# head b\nb: \"two\" # line b\nc: 3\nd: 4\n# closing\n",
+	}, {
+		name: "insert first",
+		edit: func(m *Node) error { return m.InsertKey(0, "z", scalar("0")) },
+		want: "z: 0\n# head a\na: 1 # line a\n# head b\nb: \"two\" # line b\nc: 3\n# closing\n",
+	}, {
+		name: "delete middle takes its comments",
+		edit: func(m *Node) error { return m.DeleteKey("b") },
+		want: "# head a\na: 1 # line a\nc: 3\n# closing\n",
+	}, {
+		name: "delete last keeps closing comment",
+		edit: func(m *Node) error { return m.DeleteKey("c") },
+		want: "# head a\na: 1 # line a\n# head b\nb: \"two\" # line b\n# closing\n",
+	}, {
+		name: "move last to front",
+		edit: func(m *Node) error { return m.MoveKey("c", 0) },
+		want: "c: 3\n# head a\na: 1 # line a\n# head b\nb: \"two\" # line b\n# closing\n",
+	}, {
+		name: "move first to end",
+		edit: func(m *Node) error { return m.MoveKey("a", -1) },
+		want: "# head b\nb: \"two\" # line b\nc: 3\n# head a\na: 1 # line a\n# closing\n",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := editDoc(t, src, func(doc *Node) error {
+				return tc.edit(doc.Content[0])
+			})
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestEditSequence(t *testing.T) {
+	const src = `- a # line a
+# head b
+- b
+- c
+# closing
+`
+	tests := []struct {
+		name string
+		edit func(s *Node) error
+		want string
+	}{{
+		name: "set",
+		edit: func(s *Node) error { return s.SetIndex(0, scalar("x")) },
+		want: "- x # line a\n# head b\n- b\n- c\n# closing\n",
+	}, {
+		name: "append",
+		edit: func(s *Node) error { return s.InsertIndex(3, scalar("d")) },
+		want: "- a # line a\n# head b\n- b\n- c\n- d\n# closing\n",
+	}, {
+		name: "insert before negative index",
+		edit: func(s *Node) error { return s.InsertIndex(-1, scalar("x")) },
+		want: "- a # line a\n# head b\n- b\n- x\n- c\n# closing\n",
+	}, {
+		name: "delete last",
+		edit: func(s *Node) error { return s.DeleteIndex(-1) },
+		want: "- a # line a\n# head b\n- b\n# closing\n",
+	}, {
+		name: "move",
+		edit: func(s *Node) error { return s.MoveIndex(1, 0) },
+		want: "# head b\n- b\n- a # line a\n- c\n# closing\n",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := editDoc(t, src, func(doc *Node) error {
+				return tc.edit(doc.Content[0])
+			})
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestEditPath(t *testing.T) {
+	const src = `spec:
+  replicas: 1 # keep small
+  containers:
+  - name: web
+    image: nginx
+  - name: debug
+    image: busybox
+`
+	tests := []struct {
+		name string
+		edit func(doc *Node) error
+		want string
+	}{{
+		name: "set scalar",
+		edit: func(doc *Node) error { return doc.SetPath("spec.replicas", scalar("3")) },
+		want: "spec:\n  replicas: 3 # keep small\n  containers:\n  - name: web\n    image: nginx\n  - name: debug\n    image: busybox\n",
+	}, {
+		name: "set under wildcard",
+		edit: func(doc *Node) error { return doc.SetPath("spec.containers[*].pull", scalar("Always")) },
+		want: "spec:\n  replicas: 1 # keep small\n  containers:\n  - name: web\n    image: nginx\n    pull: Always\n  - name: debug\n    image: busybox\n    pull: Always\n",
+	}, {
+		name: "set creates mappings",
+		edit: func(doc *Node) error { return doc.SetPath("meta.labels.app", scalar("web")) },
+		want: "spec:\n  replicas: 1 # keep small\n  containers:\n  - name: web\n    image: nginx\n  - name: debug\n    image: busybox\nmeta:\n  labels:\n    app: web\n",
+	}, {
+		name: "delete with filter",
+		edit: func(doc *Node) error { return doc.DeletePath("spec.containers[?(@.name == 'debug')]") },
+		want: "spec:\n  replicas: 1 # keep small\n  containers:\n  - name: web\n    image: nginx\n",
+	}, {
+		name: "insert into sequence",
+		edit: func(doc *Node) error {
+			return doc.InsertPath("spec.containers[0]", &Node{Kind: MappingNode, Content: []*Node{scalar("name"), scalar("init")}})
+		},
+		want: "spec:\n  replicas: 1 # keep small\n  containers:\n  - name: init\n  - name: web\n    image: nginx\n  - name: debug\n    image: busybox\n",
+	}, {
+		name: "move",
+		edit: func(doc *Node) error { return doc.MovePath("spec.replicas", "replicas") },
+		want: "spec:\n  containers:\n  - name: web\n    image: nginx\n  - name: debug\n    image: busybox\nreplicas: 1 # keep small\n",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, editDoc(t, src, tc.edit))
+		})
+	}
+}
+
+func TestEditErrors(t *testing.T) {
+	var doc Node
+	assert.NoError(t, Load([]byte("a: 1\nl: [1, 2]\n"), &doc))
+	m := doc.Content[0]
+	l := m.Content[3]
+
+	err := m.DeleteKey("missing")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.ErrorMatches(t, `yaml: not found: key "missing"`, err)
+
+	assert.ErrorMatches(t, `yaml: cannot insert key "a": key already exists`, m.InsertKey(0, "a", scalar("x")))
+	assert.ErrorMatches(t, `yaml: cannot set key on sequence node`, l.SetKey("a", scalar("x")))
+	assert.ErrorMatches(t, `yaml: cannot set index on mapping node`, m.SetIndex(0, scalar("x")))
+	assert.ErrorMatches(t, `yaml: not found: index 5 out of range for sequence with 2 items`, l.DeleteIndex(5))
+	assert.ErrorMatches(t, `yaml: insert position 4 out of range for 2 entries`, l.InsertIndex(4, scalar("x")))
+	assert.ErrorMatches(t, `yaml: path "l\[\*\]" must end in a single key or index`, doc.SetPath("l[*]", scalar("x")))
+	assert.True(t, errors.Is(doc.DeletePath("l[?(@ > 5)]"), ErrNotFound))
+	assert.True(t, errors.Is(doc.SetPath("l[*].x.y", scalar("x")), ErrNotFound))
+	assert.ErrorMatches(t, `yaml: cannot move l\[\*\]: path matches 2 nodes`, doc.MovePath("l[*]", "b"))
+}
+
+func TestMovePathAtomic(t *testing.T) {
+	src := "# head\na: 1 # a\nl:\n- x\n- y\n# foot\n"
+	var doc Node
+	assert.NoError(t, Load([]byte(src), &doc))
+	a := doc.Content[0].Content[1]
+	for _, to := range []string{"l[5]", "l.x", "missing[0].c"} {
+		assert.NotNil(t, doc.MovePath("l[1]", to))
+		assert.NotNil(t, doc.MovePath("a", to))
+		out, err := Dump(&doc)
+		assert.NoError(t, err)
+		assert.Equalf(t, src, string(out), "MovePath to %s", to)
+	}
+	// The tree is restored in place.
+	assert.True(t, doc.Content[0].Content[1] == a)
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Event stream hooks.
+//
+// An event plugin sees the event stream between the Parser and the Composer
+// when loading, and between the Serializer and the Emitter when dumping. For
+// each event it returns the events to pass on, so it can drop, replace or
+// inject events. The stream start and end events are passed on as they are.
+
+package libyaml
+
+import "errors"
+
+// EventFunc processes an event and returns the events to pass on in its
+// place: none to drop it, the event itself to keep it, or several to inject
+// events before or after it.
+type EventFunc func(ctx *EventContext, ev Event) ([]Event, error)
+
+// EventContext tells event plugins which side of a Load or Dump call the
+// events are on.
+type EventContext struct {
+	// Stage is ParserStage for events being loaded, and SerializerStage
+	// for events being dumped.
+	Stage Stage
+
+	opts *Options // options of the Load or Dump call
+}
+
+// ChainEventFuncs returns an EventFunc that processes events with first,
+// then processes each event first passes on with then.
+func ChainEventFuncs(first, then EventFunc) EventFunc {
+	if first == nil {
+		return then
+	}
+	return func(ctx *EventContext, ev Event) ([]Event, error) {
+		events, err := first(ctx, ev)
+		if err != nil {
+			return nil, err
+		}
+		var out []Event
+		for _, e := range events {
+			next, err := then(ctx, e)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, next...)
+		}
+		return out, nil
+	}
+}
+
+// NodeEvents returns the events of n, to inject its content into the event
+// stream. A document node gives document start and end events around the
+// events of its content. Tags are kept as they are, so tags that can be
+// resolved from the style and value of a scalar are best left empty. The
+// events are made with the options of the Load or Dump call.
+func (ctx *EventContext) NodeEvents(n *Node) (events []Event, err error) {
+	defer handleErr(&err)
+	opts := ctx.opts
+	if opts == nil {
+		opts = DefaultOptions
+	}
+	s := NewSerializer(nil, opts)
+	s.events = &events
+	s.node(n, "")
+	return events, nil
+}
+
+// nextEvent reads the next event into c.event, passing the events read from
+// the Parser through the event hook.
+func (c *Composer) nextEvent() {
+	if c.eventHook == nil {
+		if err := c.Parser.Parse(&c.event); err != nil {
+			c.fail(err)
+		}
+		return
+	}
+	for len(c.pending) == 0 {
+		var ev Event
+		if err := c.Parser.Parse(&ev); err != nil {
+			c.fail(err)
+		}
+		if ev.Type == STREAM_START_EVENT || ev.Type == STREAM_END_EVENT {
+			c.event = ev
+			return
+		}
+		events, err := c.eventHook(&EventContext{Stage: ParserStage, opts: c.opts}, ev)
+		if err != nil {
+			var lerr *LoadError
+			if !errors.As(err, &lerr) {
+				lerr = NewLoadError(ParserStage, err.Error(),
+					Mark{Line: ev.StartMark.Line, Column: ev.StartMark.Column}, err)
+			}
+			c.fail(lerr)
+		}
+		c.pending = events
+	}
+	c.event = c.pending[0]
+	c.pending = c.pending[1:]
+}
+
+// hookEvent passes event through the event hook and returns the events to
+// emit in its place.
+func (s *Serializer) hookEvent(event Event) []Event {
+	if event.Type == STREAM_START_EVENT || event.Type == STREAM_END_EVENT {
+		return []Event{event}
+	}
+	events, err := s.eventHook(&EventContext{Stage: SerializerStage, opts: s.opts}, event)
+	if err != nil {
+		failDump(SerializerStage, err)
+	}
+	return events
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for event stream hooks.
+
+package libyaml
+
+import (
+	"errors"
+	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+// withEventHook sets the event hook, as WithPlugin does for an event
+// plugin.
+func withEventHook(fn EventFunc) Option {
+	return func(o *Options) error {
+		o.EventHook = fn
+		return nil
+	}
+}
+
+// redact replaces the values of password keys, and drops secret keys with
+// their values.
+func redact() EventFunc {
+	var redactNext, dropNext bool
+	return func(ctx *EventContext, ev Event) ([]Event, error) {
+		switch {
+		case redactNext:
+			redactNext = false
+			if ev.Type == SCALAR_EVENT {
+				ev.Value = []byte("***")
+			}
+			return []Event{ev}, nil
+		case dropNext:
+			// Only scalar values can be dropped without counting nesting.
+			dropNext = false
+			return nil, nil
+		case ev.Type == SCALAR_EVENT && string(ev.Value) == "password":
+			redactNext = true
+		case ev.Type == SCALAR_EVENT && string(ev.Value) == "secret":
+			dropNext = true
+			return nil, nil
+		}
+		return []Event{ev}, nil
+	}
+}
+
+func TestEventHookLoad(t *testing.T) {
+	var v map[string]any
+	err := Load([]byte("user: bob\npassword: hunter2\nsecret: x\nport: 80\n"), &v, withEventHook(redact()))
+	assert.NoError(t, err)
+	assert.DeepEqual(t, map[string]any{"user": "bob", "password": "***", "port": 80}, v)
+
+	// Tags are rewritten before they are resolved.
+	legacy := func(ctx *EventContext, ev Event) ([]Event, error) {
+		assert.Equal(t, ParserStage, ctx.Stage)
+		if string(ev.Tag) == "!legacy" {
+			ev.Tag = []byte(strTag)
+		}
+		return []Event{ev}, nil
+	}
+	var w map[string]any
+	err = Load([]byte("a: !legacy 10\nb: 10\n"), &w, withEventHook(legacy))
+	assert.NoError(t, err)
+	assert.DeepEqual(t, map[string]any{"a": "10", "b": 10}, w)
+}
+
+func TestEventHookDump(t *testing.T) {
+	v := map[string]any{"password": "hunter2", "port": 80, "secret": "x"}
+	out, err := Dump(v, withEventHook(redact()))
+	assert.NoError(t, err)
+	assert.Equal(t, "password: '***'\nport: 80\n", string(out))
+}
+
+func TestEventHookInject(t *testing.T) {
+	// Add a version key at the top of each document.
+	depth := 0
+	inject := func(ctx *EventContext, ev Event) ([]Event, error) {
+		switch ev.Type {
+		case MAPPING_START_EVENT, SEQUENCE_START_EVENT:
+			depth++
+			if depth == 1 && ev.Type == MAPPING_START_EVENT {
+				kv, err := ctx.NodeEvents(&Node{Kind: MappingNode, Content: []*Node{
+					{Kind: ScalarNode, Value: "version"},
+					{Kind: ScalarNode, Value: "1.0", Style: DoubleQuotedStyle},
+				}})
+				if err != nil {
+					return nil, err
+				}
+				// Keep the key and value, without their mapping.
+				return append([]Event{ev}, kv[1:len(kv)-1]...), nil
+			}
+		case MAPPING_END_EVENT, SEQUENCE_END_EVENT:
+			depth--
+		}
+		return []Event{ev}, nil
+	}
+
+	out, err := Dump(map[string]int{"a": 1}, withEventHook(inject))
+	assert.NoError(t, err)
+	assert.Equal(t, "version: \"1.0\"\na: 1\n", string(out))
+
+	var v map[string]any
+	assert.NoError(t, Load([]byte("a: {b: 1}\n"), &v, withEventHook(inject)))
+	assert.DeepEqual(t, map[string]any{"version": "1.0", "a": map[string]any{"b": 1}}, v)
+}
+
+func TestEventHookErrors(t *testing.T) {
+	errNoAnchors := errors.New("anchors are not allowed")
+	noAnchors := func(ctx *EventContext, ev Event) ([]Event, error) {
+		if len(ev.Anchor) > 0 && ev.Type != ALIAS_EVENT {
+			return nil, errNoAnchors
+		}
+		return []Event{ev}, nil
+	}
+	var v any
+	err := Load([]byte("a: 1\nb: &x 2\n"), &v, withEventHook(noAnchors))
+	var lerr *LoadError
+	assert.True(t, errors.As(err, &lerr))
+	assert.Equal(t, "go-yaml load error in parser at L2.C4: anchors are not allowed", lerr.Error())
+	assert.True(t, errors.Is(err, errNoAnchors))
+
+	_, err = Dump(&Node{Kind: ScalarNode, Value: "x", Anchor: "x"}, withEventHook(noAnchors))
+	var derr *DumpError
+	assert.True(t, errors.As(err, &derr))
+	assert.True(t, errors.Is(err, errNoAnchors))
+
+	// Dropping a collection end leaves an invalid stream.
+	dropEnd := func(ctx *EventContext, ev Event) ([]Event, error) {
+		if ev.Type == SEQUENCE_END_EVENT {
+			return nil, nil
+		}
+		return []Event{ev}, nil
+	}
+	err = Load([]byte("[1]\n"), &v, withEventHook(dropEnd))
+	assert.ErrorMatches(t, "go-yaml load error in composer at L2.C1: unexpected document end event", err)
+}
+
+func TestChainEventFuncs(t *testing.T) {
+	// Each function sees the events the previous one passed on.
+	double := func(ctx *EventContext, ev Event) ([]Event, error) {
+		if ev.Type == SCALAR_EVENT && string(ev.Value) == "x" {
+			return []Event{ev, ev}, nil
+		}
+		return []Event{ev}, nil
+	}
+	upper := func(ctx *EventContext, ev Event) ([]Event, error) {
+		if ev.Type == SCALAR_EVENT && string(ev.Value) == "x" {
+			ev.Value = []byte("X")
+		}
+		return []Event{ev}, nil
+	}
+	var v []string
+	err := Load([]byte("[x, y]\n"), &v, withEventHook(ChainEventFuncs(ChainEventFuncs(nil, double), upper)))
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"X", "X", "y"}, v)
+
+	errStop := errors.New("stop")
+	stop := func(ctx *EventContext, ev Event) ([]Event, error) {
+		return nil, errStop
+	}
+	err = Load([]byte("[x]\n"), &v, withEventHook(ChainEventFuncs(double, stop)))
+	assert.True(t, errors.Is(err, errStop))
+}
+
+func TestEventContextOptions(t *testing.T) {
+	// Injected events are made with the options of the Dump call.
+	inject := func(ctx *EventContext, ev Event) ([]Event, error) {
+		if ev.Type != SCALAR_EVENT || string(ev.Value) != "list" {
+			return []Event{ev}, nil
+		}
+		list, err := ctx.NodeEvents(&Node{Kind: SequenceNode, Content: []*Node{
+			{Kind: ScalarNode, Value: "1"},
+			{Kind: ScalarNode, Value: "2"},
+		}})
+		if err != nil {
+			return nil, err
+		}
+		return append([]Event{ev}, list...), nil
+	}
+	drop := func(ctx *EventContext, ev Event) ([]Event, error) {
+		if ev.Type == SCALAR_EVENT && string(ev.Value) == "none" {
+			return nil, nil
+		}
+		return []Event{ev}, nil
+	}
+	v := map[string]any{"list": "none", "map": map[string]int{"a": 1}}
+	out, err := Dump(v, withEventHook(ChainEventFuncs(inject, drop)), WithFlowSimpleCollections())
+	assert.NoError(t, err)
+	assert.Equal(t, "list: [1, 2]\nmap: {a: 1}\n", string(out))
+}
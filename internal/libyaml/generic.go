@@ -0,0 +1,85 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Generic helpers for loading and dumping typed values.
+//
+// The iterators returned by Documents and DocumentsAs have the shape of
+// iter.Seq2 from Go 1.23, so they can be ranged over with newer Go versions
+// and called with a yield function with older ones.
+
+package libyaml
+
+import (
+	"errors"
+	"io"
+)
+
+// LoadAs loads a YAML document into a new value of type T and returns it.
+// It accepts the same options as [Load].
+//
+//	cfg, err := yaml.LoadAs[Config](data)
+func LoadAs[T any](in []byte, opts ...Option) (T, error) {
+	var v T
+	err := Load(in, &v, opts...)
+	return v, err
+}
+
+// DumpAll encodes each value in docs as a separate YAML document, like
+// [Dump] with [WithAllDocuments].
+func DumpAll[T any](docs []T, opts ...Option) ([]byte, error) {
+	opts = append(opts[:len(opts):len(opts)], WithAllDocuments())
+	return Dump(docs, opts...)
+}
+
+// Documents returns an iterator over the remaining documents of the
+// stream, as document nodes. The iteration ends at the end of the stream,
+// without an error, or after yielding the first error.
+//
+//	for doc, err := range loader.Documents() {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    ...
+//	}
+func (l *Loader) Documents() func(yield func(*Node, error) bool) {
+	return func(yield func(*Node, error) bool) {
+		for {
+			var n Node
+			err := l.Load(&n)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(&n, nil) {
+				return
+			}
+		}
+	}
+}
+
+// DocumentsAs returns an iterator over the remaining documents of the
+// stream loaded into values of type T. The iteration ends at the end of the
+// stream, without an error.
+//
+// A document with values that could not be loaded into T is yielded with
+// its *LoadErrors and the iteration continues with the next document, as
+// with [Loader.Load]. The iteration ends after yielding any other error,
+// such as a syntax error.
+func DocumentsAs[T any](l *Loader) func(yield func(T, error) bool) {
+	return func(yield func(T, error) bool) {
+		for {
+			var v T
+			err := l.Load(&v)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			var lerrs *LoadErrors
+			if !yield(v, err) || err != nil && !errors.As(err, &lerrs) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for the generic helpers.
+
+package libyaml
+
+import (
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+type genericDoc struct {
+	Name  string `yaml:"name"`
+	Count int    `yaml:"count"`
+}
+
+func TestLoadAs(t *testing.T) {
+	doc, err := LoadAs[genericDoc]([]byte("name: a\ncount: 2\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, genericDoc{"a", 2}, doc)
+
+	docs, err := LoadAs[[]genericDoc]([]byte("name: a\n---\nname: b\n"), WithAllDocuments())
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []genericDoc{{Name: "a"}, {Name: "b"}}, docs)
+
+	_, err = LoadAs[genericDoc]([]byte("count: many\n"))
+	assert.ErrorMatches(t, "yaml: construct errors: line 1: cannot construct !!str `many` into int", err)
+}
+
+func TestDumpAll(t *testing.T) {
+	opts := make([]Option, 1, 2)
+	opts[0] = WithIndent(4)
+	out, err := DumpAll([]genericDoc{{"a", 1}, {"b", 2}}, opts...)
+	assert.NoError(t, err)
+	assert.Equal(t, "name: a\ncount: 1\n---\nname: b\ncount: 2\n", string(out))
+	assert.Equal(t, 1, len(opts))
+
+	out, err = DumpAll([]int{})
+	assert.NoError(t, err)
+	assert.Equal(t, "", string(out))
+}
+
+func TestLoaderDocuments(t *testing.T) {
+	l, err := NewLoader(strings.NewReader("a: 1\n---\n- b\n---\nc\n"))
+	assert.NoError(t, err)
+	var kinds []Kind
+	l.Documents()(func(n *Node, err error) bool {
+		assert.NoError(t, err)
+		assert.Equal(t, DocumentNode, n.Kind)
+		kinds = append(kinds, n.Content[0].Kind)
+		return true
+	})
+	assert.DeepEqual(t, []Kind{MappingNode, SequenceNode, ScalarNode}, kinds)
+
+	// Stopping early leaves the other documents to read.
+	l, err = NewLoader(strings.NewReader("1\n---\n2\n---\n3\n"))
+	assert.NoError(t, err)
+	l.Documents()(func(n *Node, err error) bool {
+		return false
+	})
+	var v int
+	assert.NoError(t, l.Load(&v))
+	assert.Equal(t, 2, v)
+}
+
+func TestDocumentsAs(t *testing.T) {
+	src := "name: a\n---\ncount: x\n---\nname: c\n---\n[\n---\nname: e\n"
+	l, err := NewLoader(strings.NewReader(src))
+	assert.NoError(t, err)
+	var names []string
+	var errs []string
+	DocumentsAs[genericDoc](l)(func(doc genericDoc, err error) bool {
+		if err != nil {
+			errs = append(errs, err.Error())
+		} else {
+			names = append(names, doc.Name)
+		}
+		return true
+	})
+	// Type errors continue with the next document, syntax errors end the
+	// iteration.
+	assert.DeepEqual(t, []string{"a", "c"}, names)
+	assert.Equal(t, 2, len(errs))
+	assert.True(t, strings.Contains(errs[0], "cannot construct !!str `x` into int"))
+	assert.True(t, strings.Contains(errs[1], "did not find expected node content"))
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Mapping key order.
+//
+// Go maps have no order, so the Representer sorts their keys. By default it
+// uses a natural order, where numbers sort numerically and "a2" comes
+// before "a10". WithMapKeyOrder replaces the order of string keys, for
+// example to put well-known keys first.
+
+package libyaml
+
+import (
+	"reflect"
+	"sort"
+)
+
+// KeyOrderFunc reports whether mapping key a is dumped before key b.
+type KeyOrderFunc func(a, b string) bool
+
+var (
+	// InsertionKeyOrder keeps the keys of a [MapSlice] in the order of its
+	// items, and sorts the keys of Go maps with [NaturalKeyOrder]. It is
+	// the default.
+	InsertionKeyOrder KeyOrderFunc
+
+	// NaturalKeyOrder sorts keys with natural number ordering, so that
+	// "a2" comes before "a10".
+	NaturalKeyOrder KeyOrderFunc = naturalLess
+
+	// LexicalKeyOrder sorts keys by comparing their bytes.
+	LexicalKeyOrder KeyOrderFunc = func(a, b string) bool { return a < b }
+)
+
+// PriorityKeyOrder returns an order that puts the given keys first, in the
+// order given, followed by the other keys in natural order. For Kubernetes
+// manifests:
+//
+//	PriorityKeyOrder("apiVersion", "kind", "metadata", "spec")
+func PriorityKeyOrder(first ...string) KeyOrderFunc {
+	rank := make(map[string]int, len(first))
+	for i, k := range first {
+		if _, ok := rank[k]; !ok {
+			rank[k] = i
+		}
+	}
+	return func(a, b string) bool {
+		ar, aok := rank[a]
+		br, bok := rank[b]
+		switch {
+		case aok && bok:
+			return ar < br
+		case aok || bok:
+			return aok
+		}
+		return naturalLess(a, b)
+	}
+}
+
+// sortKeys sorts map keys in the order set with WithMapKeyOrder.
+func (r *Representer) sortKeys(keys keyList) {
+	if r.keyOrder == nil {
+		sort.Sort(keys)
+		return
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return r.keyLess(keys[i], keys[j])
+	})
+}
+
+// keyLess compares two keys with the order set with WithMapKeyOrder. Keys
+// that are not both strings are compared in natural order.
+func (r *Representer) keyLess(a, b reflect.Value) bool {
+	a, b = keyValue(a), keyValue(b)
+	if a.Kind() == reflect.String && b.Kind() == reflect.String {
+		return r.keyOrder(a.String(), b.String())
+	}
+	return keyList{a, b}.Less(0, 1)
+}
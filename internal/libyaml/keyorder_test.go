@@ -0,0 +1,67 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for mapping key order.
+
+package libyaml
+
+import (
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+func TestMapKeyOrder(t *testing.T) {
+	m := map[string]int{"a10": 1, "a2": 2, "B": 3, "kind": 4, "apiVersion": 5}
+	tests := []struct {
+		name  string
+		order KeyOrderFunc
+		want  []string
+	}{
+		{"default", InsertionKeyOrder, []string{"B", "a2", "a10", "apiVersion", "kind"}},
+		{"natural", NaturalKeyOrder, []string{"B", "a2", "a10", "apiVersion", "kind"}},
+		{"lexical", LexicalKeyOrder, []string{"B", "a10", "a2", "apiVersion", "kind"}},
+		{"priority", PriorityKeyOrder("apiVersion", "kind", "missing"), []string{"apiVersion", "kind", "B", "a2", "a10"}},
+		{"custom", func(a, b string) bool { return len(a) > len(b) }, []string{"apiVersion", "kind", "a10", "a2", "B"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Dump(m, WithMapKeyOrder(tt.order))
+			assert.NoError(t, err)
+			var keys []string
+			for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+				keys = append(keys, strings.SplitN(line, ":", 2)[0])
+			}
+			assert.DeepEqual(t, tt.want, keys)
+		})
+	}
+}
+
+func TestMapKeyOrderMixedKeys(t *testing.T) {
+	// Keys that are not both strings keep their natural order.
+	m := map[any]int{"b": 1, "a": 2, 10: 3, 2: 4}
+	out, err := Dump(m, WithMapKeyOrder(PriorityKeyOrder("b")))
+	assert.NoError(t, err)
+	assert.Equal(t, "2: 4\n10: 3\nb: 1\na: 2\n", string(out))
+}
+
+func TestMapKeyOrderMapSlice(t *testing.T) {
+	v := struct {
+		Z MapSlice          `yaml:"z"`
+		M map[string]string `yaml:"m,inline"`
+	}{
+		Z: MapSlice{{"kind", "x"}, {"b", 1}, {"apiVersion", "v1"}},
+		M: map[string]string{"spec": "s", "metadata": "m"},
+	}
+	out, err := Dump(&v)
+	assert.NoError(t, err)
+	assert.Equal(t, "z:\n  kind: x\n  b: 1\n  apiVersion: v1\nmetadata: m\nspec: s\n", string(out))
+
+	// Other orders sort MapSlice keys too, without changing the value.
+	// Struct fields keep their declaration order.
+	out, err = Dump(&v, WithMapKeyOrder(PriorityKeyOrder("apiVersion", "kind", "spec")))
+	assert.NoError(t, err)
+	assert.Equal(t, "z:\n  apiVersion: v1\n  kind: x\n  b: 1\nspec: s\nmetadata: m\n", string(out))
+	assert.DeepEqual(t, []any{"kind", "b", "apiVersion"}, v.Z.Keys())
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Resource limits for loading untrusted YAML.
+//
+// The Reader caps the input size, the Scanner caps the length of scalars,
+// and the Composer caps the number of documents in a stream and the number
+// of nodes, anchors and mapping keys in a document. Each violation stops
+// loading with a LoadError at the position where the limit was exceeded.
+
+package libyaml
+
+import "fmt"
+
+// ResourceLimits caps the resources used to load a YAML stream.
+// A zero field means no limit, which is the default.
+type ResourceLimits struct {
+	InputBytes   int // Bytes of input in the stream
+	ScalarLength int // Bytes in a single scalar value
+	Nodes        int // Nodes in a document, including aliases
+	MappingKeys  int // Keys in a single mapping
+	Documents    int // Documents in the stream
+	Anchors      int // Anchors defined in a document
+}
+
+// MergeResourceLimits returns base with the non-zero fields of set
+// replacing its own, so that the limits of several resource limit plugins
+// add up rather than the last one resetting the others.
+func MergeResourceLimits(base, set ResourceLimits) ResourceLimits {
+	if set.InputBytes != 0 {
+		base.InputBytes = set.InputBytes
+	}
+	if set.ScalarLength != 0 {
+		base.ScalarLength = set.ScalarLength
+	}
+	if set.Nodes != 0 {
+		base.Nodes = set.Nodes
+	}
+	if set.MappingKeys != 0 {
+		base.MappingKeys = set.MappingKeys
+	}
+	if set.Documents != 0 {
+		base.Documents = set.Documents
+	}
+	if set.Anchors != 0 {
+		base.Anchors = set.Anchors
+	}
+	return base
+}
+
+// checkScalarLength returns an error if the scalar value s starting at mark
+// is longer than the limit. The scanner calls it as it appends to s, so
+// that a long scalar fails before it is read whole.
+func (parser *Parser) checkScalarLength(s []byte, mark Mark) error {
+	if max := parser.limits.ScalarLength; max > 0 && len(s) > max {
+		return formatScannerError(
+			fmt.Sprintf("exceeded max scalar length of %d bytes", max), mark)
+	}
+	return nil
+}
+
+// countNode counts n against the limit of nodes in the document.
+func (c *Composer) countNode(n *Node) {
+	c.nodeCount++
+	if max := c.Parser.limits.Nodes; max > 0 && c.nodeCount > max {
+		c.failLimit(fmt.Sprintf("exceeded max node count of %d", max), n)
+	}
+}
+
+// countDocument counts the document n against the limit of documents in
+// the stream, and starts counting the nodes and anchors of n.
+func (c *Composer) countDocument(n *Node) {
+	c.nodeCount = 0
+	c.anchorCount = 0
+	c.documentCount++
+	if max := c.Parser.limits.Documents; max > 0 && c.documentCount > max {
+		c.failLimit(fmt.Sprintf("exceeded max document count of %d", max), n)
+	}
+}
+
+// countAnchor counts the anchor of n against the limit of anchors in the
+// document.
+func (c *Composer) countAnchor(n *Node) {
+	c.anchorCount++
+	if max := c.Parser.limits.Anchors; max > 0 && c.anchorCount > max {
+		c.failLimit(fmt.Sprintf("exceeded max anchor count of %d", max), n)
+	}
+}
+
+// checkMappingKeys fails if the mapping n has more keys than the limit,
+// once its key k was added and before its value is composed.
+func (c *Composer) checkMappingKeys(n, k *Node) {
+	if max := c.Parser.limits.MappingKeys; max > 0 && (len(n.Content)+1)/2 > max {
+		c.failLimit(fmt.Sprintf("exceeded max mapping size of %d keys", max), k)
+	}
+}
+
+// include composes the documents of data, the content of an included
+// file, counting them against the limits of c as part of the current
+// document.
+func (c *Composer) include(data []byte) []*Node {
+	sub := NewComposer(data, c.opts)
+	defer sub.Destroy()
+	// The reader of sub counts data after the input read so far, so that
+	// it fails where the included file runs over the input size limit.
+	sub.Parser.inputOffset = c.Parser.offset + c.includedBytes
+	c.includedBytes += len(data)
+	var docs []*Node
+	for {
+		doc := sub.Compose()
+		if doc == nil {
+			return docs
+		}
+		c.documentCount++
+		if max := c.Parser.limits.Documents; max > 0 && c.documentCount > max {
+			c.failLimit(fmt.Sprintf("exceeded max document count of %d", max), doc)
+		}
+		c.nodeCount += sub.nodeCount
+		if max := c.Parser.limits.Nodes; max > 0 && c.nodeCount > max {
+			c.failLimit(fmt.Sprintf("exceeded max node count of %d", max), doc)
+		}
+		c.anchorCount += sub.anchorCount
+		if max := c.Parser.limits.Anchors; max > 0 && c.anchorCount > max {
+			c.failLimit(fmt.Sprintf("exceeded max anchor count of %d", max), doc)
+		}
+		docs = append(docs, doc)
+	}
+}
+
+// failLimit fails with a composer error for the limit exceeded at n.
+func (c *Composer) failLimit(msg string, n *Node) {
+	c.fail(formatComposerError(msg, Mark{Line: n.Line, Column: n.Column}))
+}
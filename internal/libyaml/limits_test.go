@@ -0,0 +1,127 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for resource limits.
+
+package libyaml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+// withLimits sets the resource limits, as WithPlugin does for a resource
+// limit plugin.
+func withLimits(limits ResourceLimits) Option {
+	return func(o *Options) error {
+		o.ResourceLimits = limits
+		return nil
+	}
+}
+
+func TestResourceLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits ResourceLimits
+		src    string
+		want   string
+	}{{
+		name:   "input bytes",
+		limits: ResourceLimits{InputBytes: 10},
+		src:    "a: 1\nb: 22\n",
+		want:   "go-yaml load error in reader at L2.C4: exceeded max input size of 10 bytes",
+	}, {
+		name:   "scalar length",
+		limits: ResourceLimits{ScalarLength: 3},
+		src:    "a: abc\nb: 'abcd'\n",
+		want:   "go-yaml load error in scanner at L2.C4: exceeded max scalar length of 3 bytes",
+	}, {
+		name:   "block scalar length",
+		limits: ResourceLimits{ScalarLength: 3},
+		src:    "a: |\n  abc\n",
+		want:   "go-yaml load error in scanner at L1.C4: exceeded max scalar length of 3 bytes",
+	}, {
+		name:   "nodes",
+		limits: ResourceLimits{Nodes: 4},
+		src:    "a: [1, 2]\n",
+		want:   "go-yaml load error in composer at L1.C8: exceeded max node count of 4",
+	}, {
+		name:   "mapping keys",
+		limits: ResourceLimits{MappingKeys: 2},
+		src:    "a: 1\nb: {x: 1, y: 2}\nc: 3\n",
+		want:   "go-yaml load error in composer at L3.C1: exceeded max mapping size of 2 keys",
+	}, {
+		name:   "documents",
+		limits: ResourceLimits{Documents: 2},
+		src:    "1\n---\n2\n---\n3\n",
+		want:   "go-yaml load error in composer at L4.C1: exceeded max document count of 2",
+	}, {
+		name:   "anchors",
+		limits: ResourceLimits{Anchors: 1},
+		src:    "a: &x 1\nb: &y 2\n",
+		want:   "go-yaml load error in composer at L2.C4: exceeded max anchor count of 1",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v []any
+			err := Load([]byte(tt.src), &v, withLimits(tt.limits), WithAllDocuments())
+			var lerr *LoadError
+			assert.True(t, errors.As(err, &lerr))
+			assert.Equal(t, tt.want, lerr.Error())
+		})
+	}
+}
+
+func TestResourceLimitsWithinLimits(t *testing.T) {
+	limits := ResourceLimits{
+		InputBytes:   22,
+		ScalarLength: 3,
+		Nodes:        5,
+		MappingKeys:  2,
+		Documents:    2,
+		Anchors:      1,
+	}
+	var v []any
+	err := Load([]byte("a: &x abc\nb: *x\n---\n1\n"), &v, withLimits(limits), WithAllDocuments())
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []any{map[string]any{"a": "abc", "b": "abc"}, 1}, v)
+}
+
+func TestResourceLimitsPerDocument(t *testing.T) {
+	// Node and anchor counts start again with each document.
+	l, err := NewLoader(strings.NewReader("&a [1]\n---\n&b [2]\n"), withLimits(ResourceLimits{Nodes: 2, Anchors: 1}))
+	assert.NoError(t, err)
+	var v []int
+	assert.NoError(t, l.Load(&v))
+	assert.NoError(t, l.Load(&v))
+	assert.DeepEqual(t, []int{2}, v)
+}
+
+// endlessScalar reads prefix and then an endless run of x bytes.
+type endlessScalar struct {
+	prefix string
+}
+
+func (r *endlessScalar) Read(p []byte) (int, error) {
+	n := copy(p, r.prefix)
+	r.prefix = r.prefix[n:]
+	for i := n; i < len(p); i++ {
+		p[i] = 'x'
+	}
+	return len(p), nil
+}
+
+func TestResourceLimitsEndlessScalar(t *testing.T) {
+	// The scalar length is checked while the scalar is scanned, so
+	// a scalar that never ends fails.
+	for _, prefix := range []string{"a: ", "a: 'x", "a: \"x", "a: |\n  ", "a: >\n  "} {
+		l, err := NewLoader(&endlessScalar{prefix}, withLimits(ResourceLimits{ScalarLength: 1000}))
+		assert.NoError(t, err)
+		var v any
+		err = l.Load(&v)
+		assert.ErrorMatchesf(t, ".*L1.C4: exceeded max scalar length of 1000 bytes", err, "prefix %q", prefix)
+	}
+}
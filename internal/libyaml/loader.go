@@ -137,7 +137,9 @@ func (l *Loader) Load(v any) (err error) {
 	l.docCount++
 
 	// Stage 2: Resolve - determine implicit types for untagged scalars
+	l.selectSchema()
 	l.resolver.Resolve(node)
+	node = l.transformLoaded(node)
 
 	// Stage 3: Construct - convert node tree to Go values
 	out := reflect.ValueOf(v)
@@ -146,9 +148,7 @@ func (l *Loader) Load(v any) (err error) {
 	}
 	l.constructor.Construct(node, out)
 	if len(l.constructor.TypeErrors) > 0 {
-		typeErrors := l.constructor.TypeErrors
-		l.constructor.TypeErrors = nil
-		return &LoadErrors{Errors: typeErrors}
+		return l.constructor.takeErrors()
 	}
 	return nil
 }
@@ -189,6 +189,7 @@ func loadAll(in []byte, out any, opts *Options) error {
 	}
 
 	elemType := sliceVal.Type().Elem()
+	var collected []*LoadError
 	for {
 		// Create new element of slice's element type
 		elemPtr := reflect.New(elemType)
@@ -197,12 +198,34 @@ func loadAll(in []byte, out any, opts *Options) error {
 			break
 		}
 		if err != nil {
-			return err
+			if !opts.CollectErrors {
+				return err
+			}
+			// Keep the errors of every document, and go on with the
+			// next one unless the stream can't be read further.
+			var lerrs *LoadErrors
+			var lerr *LoadError
+			switch {
+			case errors.As(err, &lerrs):
+				collected = append(collected, lerrs.Errors...)
+			case errors.As(err, &lerr):
+				collected = append(collected, lerr)
+				return &LoadErrors{Errors: collected}
+			default:
+				return err
+			}
+			if max := opts.MaxErrors; max > 0 && len(collected) > max {
+				return &LoadErrors{Errors: append(collected[:max:max],
+					formatConstructorError(errTooManyErrors, collected[max].Mark))}
+			}
 		}
 		// Append loaded element to slice
 		sliceVal.Set(reflect.Append(sliceVal, elemPtr.Elem()))
 	}
 
+	if len(collected) > 0 {
+		return &LoadErrors{Errors: collected}
+	}
 	return nil
 }
 
@@ -267,6 +290,19 @@ func (l *Loader) SetKnownFields(enable bool) {
 	l.constructor.KnownFields = enable
 }
 
+// selectSchema sets the schema for the document just composed, which may
+// depend on its %YAML directive.
+func (l *Loader) selectSchema() {
+	schema := l.options.Schema
+	if l.options.SchemaFromVersion {
+		if s, ok := versionSchema(l.composer.version); ok {
+			schema = s
+		}
+	}
+	l.resolver.schema = schema
+	l.constructor.schema = schema
+}
+
 // ComposeAndResolve composes and resolves the next document from the input
 // and returns the node without constructing Go values. This is used by
 // Unmarshal() to support the Unmarshaler interface.
@@ -283,7 +319,9 @@ func (l *Loader) ComposeAndResolve() *Node {
 	l.docCount++
 
 	// Stage 2: Resolve - determine implicit types for untagged scalars
+	l.selectSchema()
 	l.resolver.Resolve(node)
+	node = l.transformLoaded(node)
 
 	return node
 }
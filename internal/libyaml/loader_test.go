@@ -308,3 +308,127 @@ func TestLoad_MultipleDocuments(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.ErrorMatches(t, ".*expected single document, found multiple.*", err)
 }
+
+type collectConfig struct {
+	Name  string      `yaml:"name"`
+	Port  int         `yaml:"port"`
+	Tags  map[any]any `yaml:"tags"`
+	Point [2]int      `yaml:"point"`
+}
+
+// loadErrorLines returns the messages of the errors in err.
+func loadErrorLines(t *testing.T, err error) []string {
+	t.Helper()
+	lerrs, ok := err.(*LoadErrors)
+	assert.Truef(t, ok, "want *LoadErrors, got %T: %v", err, err)
+	var lines []string
+	for _, e := range lerrs.Errors {
+		lines = append(lines, e.simpleError())
+	}
+	return lines
+}
+
+// TestLoad_CollectErrors tests that WithCollectErrors reports every error
+func TestLoad_CollectErrors(t *testing.T) {
+	input := []byte("name: a\n" +
+		"name: b\n" +
+		"port: x\n" +
+		"extra: 1\n" +
+		"tags: {[a]: 1, ok: 2}\n" +
+		"point: [1, 2, 3]\n" +
+		"more: 2\n")
+
+	var config collectConfig
+	err := Load(input, &config, WithKnownFields(), WithCollectErrors())
+	assert.DeepEqual(t, []string{
+		`line 2: mapping key "name" already defined at line 1`,
+		"line 3: cannot construct !!str `x` into int",
+		"line 4: field extra not found in type libyaml.collectConfig",
+		"line 5: cannot use '[]interface {}{\"a\"}' as a map key; try decoding into yaml.Node",
+		"line 6: invalid array: want 2 elements but got 3",
+		"line 7: field more not found in type libyaml.collectConfig",
+	}, loadErrorLines(t, err))
+	assert.Equal(t, "a", config.Name)
+	assert.DeepEqual(t, map[any]any{"ok": 2}, config.Tags)
+
+	// Without the option, duplicate keys stop the mapping and invalid map
+	// keys stop loading.
+	err = Load(input, &config, WithKnownFields())
+	assert.DeepEqual(t, []string{`line 2: mapping key "name" already defined at line 1`}, loadErrorLines(t, err))
+	err = Load([]byte("tags: {[a]: 1}\nport: x\n"), &config)
+	assert.ErrorMatches(t, `.*at L1.C8: cannot use .* as a map key; try decoding into yaml.Node`, err)
+}
+
+// TestLoad_CollectErrorsAllDocuments tests that errors of all documents
+// are reported together
+func TestLoad_CollectErrorsAllDocuments(t *testing.T) {
+	input := []byte("port: x\n---\nname: ok\n---\nport: y\nname: [z]\n---\n[\n")
+
+	var configs []collectConfig
+	err := Load(input, &configs, WithAllDocuments(), WithCollectErrors())
+	assert.DeepEqual(t, []string{
+		"line 1: cannot construct !!str `x` into int",
+		"line 5: cannot construct !!str `y` into int",
+		"line 6: cannot construct !!seq into string",
+		"while parsing a flow node at line 9, column 1: did not find expected node content",
+	}, loadErrorLines(t, err))
+	assert.Equal(t, 3, len(configs))
+	assert.Equal(t, "ok", configs[1].Name)
+
+	err = Load(input, &configs, WithAllDocuments())
+	assert.DeepEqual(t, []string{"line 1: cannot construct !!str `x` into int"}, loadErrorLines(t, err))
+}
+
+// TestLoad_MaxErrorsAtLimit tests that loading goes on when the errors
+// only reach the error limit
+func TestLoad_MaxErrorsAtLimit(t *testing.T) {
+	type fields struct{ A, B, C, D int }
+	input := []byte("a: x\nb: y\nc: 3\nd: 4\n")
+	for _, opts := range [][]Option{
+		{WithMaxErrors(2)},
+		{WithMaxErrors(2), WithCollectErrors()},
+	} {
+		var v fields
+		err := Load(input, &v, opts...)
+		assert.DeepEqual(t, []string{
+			"line 1: cannot construct !!str `x` into int",
+			"line 2: cannot construct !!str `y` into int",
+		}, loadErrorLines(t, err))
+		assert.Equal(t, fields{C: 3, D: 4}, v)
+	}
+
+	docs := []byte("port: x\n---\nport: y\n---\nname: z\n")
+	var configs []collectConfig
+	err := Load(docs, &configs, WithAllDocuments(), WithCollectErrors(), WithMaxErrors(2))
+	assert.Equal(t, 2, len(loadErrorLines(t, err)))
+	assert.Equal(t, 3, len(configs))
+	assert.Equal(t, "z", configs[2].Name)
+}
+
+// TestLoad_MaxErrors tests that loading stops at the error limit
+func TestLoad_MaxErrors(t *testing.T) {
+	input := []byte("a: 1\nb: 2\nc: 3\nd: 4\n")
+
+	var config collectConfig
+	err := Load(input, &config, WithKnownFields(), WithMaxErrors(2))
+	assert.DeepEqual(t, []string{
+		"line 1: field a not found in type libyaml.collectConfig",
+		"line 2: field b not found in type libyaml.collectConfig",
+		"line 3: too many errors",
+	}, loadErrorLines(t, err))
+
+	err = Load(input, &config, WithKnownFields(), WithMaxErrors(4))
+	assert.Equal(t, 4, len(loadErrorLines(t, err)))
+
+	docs := []byte("port: x\n---\nport: y\n---\nport: z\n")
+	var configs []collectConfig
+	err = Load(docs, &configs, WithAllDocuments(), WithCollectErrors(), WithMaxErrors(2))
+	assert.DeepEqual(t, []string{
+		"line 1: cannot construct !!str `x` into int",
+		"line 3: cannot construct !!str `y` into int",
+		"line 5: too many errors",
+	}, loadErrorLines(t, err))
+
+	err = Load(input, &config, WithMaxErrors(-1))
+	assert.ErrorMatches(t, "yaml: WithMaxErrors needs a limit of 0 or more", err)
+}
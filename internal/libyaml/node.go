@@ -367,6 +367,7 @@ func (n *Node) Dump(v any, opts ...Option) (err error) {
 	// Use the 3-stage dump pipeline with round-trip to preserve styles
 	r := NewRepresenter(o)
 	node := r.Represent("", reflect.ValueOf(v))
+	node = transformDumped(o, node)
 	d := NewDesolver(o)
 	d.Desolve(node)
 	s := NewSerializer(nil, o)
@@ -424,6 +425,33 @@ type ToYAMLNode interface {
 	ToYAMLNode() (*Node, error)
 }
 
+// isZeroerType holds the [reflect.Type] of the IsZeroer interface.
+var isZeroerType = reflect.TypeOf((*IsZeroer)(nil)).Elem()
+
+// isOmitZero reports whether a field tagged ,omitzero holding v is omitted:
+// when its type has an IsZero method, on the value or on a pointer to it,
+// that method decides; otherwise v must be the zero value of its type.
+// Unlike isZero, empty slices and maps are kept, and structs are compared
+// as a whole, matching omitzero in encoding/json.
+func isOmitZero(v reflect.Value) bool {
+	t := v.Type()
+	if t.Implements(isZeroerType) {
+		if (t.Kind() == reflect.Pointer || t.Kind() == reflect.Interface) && v.IsNil() {
+			return true
+		}
+		return v.Interface().(IsZeroer).IsZero()
+	}
+	if reflect.PointerTo(t).Implements(isZeroerType) {
+		if !v.CanAddr() {
+			c := reflect.New(t).Elem()
+			c.Set(v)
+			v = c
+		}
+		return v.Addr().Interface().(IsZeroer).IsZero()
+	}
+	return v.IsZero()
+}
+
 // isZero reports whether v represents the zero value for its type.
 // If v implements the IsZeroer interface, IsZero() is called.
 // Otherwise, zero is determined by checking type-specific conditions.
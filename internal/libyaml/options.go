@@ -10,6 +10,7 @@ package libyaml
 import (
 	"errors"
 	"fmt"
+	"reflect"
 )
 
 // Options holds configuration for both loading and dumping YAML.
@@ -18,8 +19,21 @@ type Options struct {
 	KnownFields    bool // Enforce known fields in structs
 	SingleDocument bool // Only load first document
 	UniqueKeys     bool // Enforce unique keys in mappings
-	StreamNodes    bool // Enable stream node emission
-	AllDocuments   bool // Load/Dump all documents in multi-document streams
+
+	CaseInsensitiveKeys bool              // Match struct keys ignoring case
+	DeprecatedKey       DeprecatedKeyFunc // Called for struct keys matched by alias
+	UseNumber           bool              // Load numbers into interfaces as Number
+	OrderedMaps         bool              // Load mappings into interfaces as MapSlice
+	CollectErrors       bool              // Report recoverable errors together instead of stopping
+	MaxErrors           int               // Maximum number of errors reported (0 for no limit)
+	StreamNodes         bool              // Enable stream node emission
+	AllDocuments        bool              // Load/Dump all documents in multi-document streams
+
+	// Loading and dumping options
+	FieldNaming       FieldNaming // Key naming for untagged struct fields
+	JSONTags          bool        // Use json tags of fields without a yaml tag
+	Schema            Schema      // Resolution schema for plain scalars
+	SchemaFromVersion bool        // Select the schema from %YAML directives
 
 	// Dumping options
 	Indent                int        // Indentation spaces (2-9)
@@ -33,10 +47,29 @@ type Options struct {
 	FlowSimpleCollections bool       // Use flow style for simple collections
 	QuotePreference       QuoteStyle // Preferred quote style when quoting is required
 
+	MapKeyOrder KeyOrderFunc // Order of string map keys (nil for the default)
+
 	// Safety limit checks (set by ApplyOptions or WithPlugin(limit.New(...)))
 	DepthCheck func(depth int, ctx *DepthContext) error
 	AliasCheck func(aliasCount, constructCount int) error
 
+	// Resource limits (set by WithPlugin with a resource limit plugin)
+	ResourceLimits ResourceLimits
+
+	// Application tags (set by WithPlugin with a tag or resolver plugin)
+	TagConstruct TagConstructFunc
+	TagRepresent TagRepresentFunc
+	ImplicitTag  func(value string) string
+
+	// Event stream hook (set by WithPlugin with an event plugin)
+	EventHook EventFunc
+
+	// Document node hook (set by WithPlugin with a node plugin)
+	NodeHook NodeFunc
+
+	// Concrete types of interfaces, by interface type (set by WithDiscriminator)
+	Discriminators map[reflect.Type]*discriminator
+
 	// Private options (not exported, used internally)
 	FromLegacy bool // Indicates legacy Unmarshal()/Decoder path (check Unmarshaler, allow trailing content)
 }
@@ -279,6 +312,45 @@ func WithUniqueKeys(uniqueKeys ...bool) Option {
 	}
 }
 
+// WithCollectErrors makes loading continue after errors that leave the
+// rest of the input readable, so that one *LoadErrors reports all of them:
+// duplicate keys, invalid map keys and arrays of the wrong length are
+// reported with the type errors, unknown fields and failing unmarshalers
+// that are always collected. With WithAllDocuments, loading continues with
+// the next document after a document with errors. Syntax errors still stop
+// loading.
+// When called without arguments, defaults to true.
+//
+// The default is false.
+func WithCollectErrors(enable ...bool) Option {
+	if len(enable) > 1 {
+		return func(o *Options) error {
+			return errors.New("yaml: WithCollectErrors accepts at most one argument")
+		}
+	}
+	val := len(enable) == 0 || enable[0]
+	return func(o *Options) error {
+		o.CollectErrors = val
+		return nil
+	}
+}
+
+// WithMaxErrors sets the maximum number of errors reported by a load.
+// Loading stops once an error beyond the limit is found, and the returned
+// *LoadErrors ends with a "too many errors" error in its place. Set to 0
+// for no limit.
+//
+// The default is 0.
+func WithMaxErrors(max int) Option {
+	return func(o *Options) error {
+		if max < 0 {
+			return errors.New("yaml: WithMaxErrors needs a limit of 0 or more")
+		}
+		o.MaxErrors = max
+		return nil
+	}
+}
+
 // WithCanonical forces canonical YAML output format.
 //
 // When enabled, the encoder outputs strictly canonical YAML with explicit
@@ -399,6 +471,183 @@ func WithQuotePreference(style QuoteStyle) Option {
 	}
 }
 
+// WithMapKeyOrder sets the order in which the string keys of maps and
+// [MapSlice] values are dumped. Keys that are not both strings keep their
+// natural order, and struct fields keep their declaration order.
+//
+// Presets:
+//   - InsertionKeyOrder: MapSlice order, natural order for maps (default)
+//   - NaturalKeyOrder: natural number ordering, so "a2" comes before "a10"
+//   - LexicalKeyOrder: byte-wise comparison
+//   - PriorityKeyOrder(keys...): the given keys first, then natural order
+func WithMapKeyOrder(order KeyOrderFunc) Option {
+	return func(o *Options) error {
+		o.MapKeyOrder = order
+		return nil
+	}
+}
+
+// WithDiscriminator registers the concrete types that values of the
+// interface type T are loaded into, by name. A value of type T is loaded
+// from a mapping whose key names a type, such as type: http, or from a
+// node with a local tag naming it, such as !http. An empty key selects
+// types by tag only. Nodes without either are loaded as usual.
+//
+// The values of types give the concrete types; they may be pointers.
+// Dumping a value held in a field, slice element or map value of type T
+// writes the key first in its mapping, or the tag when key is empty or the
+// value is not a mapping. The key is left out of structs that have no field
+// for it.
+//
+// Registering T again replaces its types.
+func WithDiscriminator[T any](key string, types map[string]T) Option {
+	d, err := newDiscriminator(key, types)
+	return func(o *Options) error {
+		if err != nil {
+			return err
+		}
+		m := make(map[reflect.Type]*discriminator, len(o.Discriminators)+1)
+		for t, d := range o.Discriminators {
+			m[t] = d
+		}
+		m[d.iface] = d
+		o.Discriminators = m
+		return nil
+	}
+}
+
+// DeprecatedKeyFunc is called when a mapping key is decoded into a struct
+// field through one of the ,alias= keys of the field. alias is the key as
+// written in the document, key the key of the field and mark the position
+// of the alias.
+type DeprecatedKeyFunc func(alias, key string, mark Mark)
+
+// WithCaseInsensitiveKeys enables or disables case-insensitive matching
+// of mapping keys to struct fields during loading. Exact matches take
+// precedence, and keys of a struct that differ only in case must be
+// matched exactly.
+// When called without arguments, defaults to true.
+func WithCaseInsensitiveKeys(enable ...bool) Option {
+	if len(enable) > 1 {
+		return func(o *Options) error {
+			return errors.New("yaml: WithCaseInsensitiveKeys accepts at most one argument")
+		}
+	}
+	val := len(enable) == 0 || enable[0]
+	return func(o *Options) error {
+		o.CaseInsensitiveKeys = val
+		return nil
+	}
+}
+
+// WithUseNumber makes loading store integers and floats held in interface
+// values as [Number] rather than int or float64, so that no precision is
+// lost.
+// When called without arguments, defaults to true.
+func WithUseNumber(enable ...bool) Option {
+	if len(enable) > 1 {
+		return func(o *Options) error {
+			return errors.New("yaml: WithUseNumber accepts at most one argument")
+		}
+	}
+	val := len(enable) == 0 || enable[0]
+	return func(o *Options) error {
+		o.UseNumber = val
+		return nil
+	}
+}
+
+// WithOrderedMaps makes loading store mappings held in interface values as
+// [MapSlice] rather than map[string]any or map[any]any, keeping the keys in
+// document order. Dumping the values writes the keys in the same order.
+// When called without arguments, defaults to true.
+func WithOrderedMaps(enable ...bool) Option {
+	if len(enable) > 1 {
+		return func(o *Options) error {
+			return errors.New("yaml: WithOrderedMaps accepts at most one argument")
+		}
+	}
+	val := len(enable) == 0 || enable[0]
+	return func(o *Options) error {
+		o.OrderedMaps = val
+		return nil
+	}
+}
+
+// WithDeprecatedKeyFunc sets a function called during loading for each
+// mapping key that matches a struct field through an ,alias= key, so that
+// old key names can be reported. A nil function disables reporting.
+func WithDeprecatedKeyFunc(fn DeprecatedKeyFunc) Option {
+	return func(o *Options) error {
+		o.DeprecatedKey = fn
+		return nil
+	}
+}
+
+// WithFieldNaming sets how struct fields without an explicit key in their
+// yaml tag are named, when loading and dumping.
+func WithFieldNaming(naming FieldNaming) Option {
+	return func(o *Options) error {
+		switch naming {
+		case LowerCase, SnakeCase, KebabCase, CamelCase, PascalCase:
+			o.FieldNaming = naming
+			return nil
+		default:
+			return fmt.Errorf("invalid FieldNaming value: %d", naming)
+		}
+	}
+}
+
+// WithJSONTags enables or disables reading the json tags of struct fields
+// that have no yaml tag, when loading and dumping. The json key and the
+// omitempty, omitzero and string options are honored, a "-" key skips the
+// field, and embedded structs without a json key are inlined.
+// When called without arguments, defaults to true.
+func WithJSONTags(enable ...bool) Option {
+	if len(enable) > 1 {
+		return func(o *Options) error {
+			return errors.New("yaml: WithJSONTags accepts at most one argument")
+		}
+	}
+	val := len(enable) == 0 || enable[0]
+	return func(o *Options) error {
+		o.JSONTags = val
+		return nil
+	}
+}
+
+// WithSchema sets the schema used to resolve the tags of plain scalars when
+// loading, and to decide which strings must be quoted when dumping.
+func WithSchema(schema Schema) Option {
+	return func(o *Options) error {
+		switch schema {
+		case DefaultSchema, CoreSchema, JSONSchema, FailsafeSchema, YAML11Schema:
+			o.Schema = schema
+			return nil
+		default:
+			return fmt.Errorf("invalid Schema value: %d", schema)
+		}
+	}
+}
+
+// WithSchemaFromVersion enables or disables selecting the schema of each
+// loaded document from its %YAML directive: YAML11Schema for %YAML 1.1 and
+// CoreSchema for %YAML 1.2. Documents without a directive use the schema
+// set by [WithSchema].
+// When called without arguments, defaults to true.
+func WithSchemaFromVersion(enable ...bool) Option {
+	if len(enable) > 1 {
+		return func(o *Options) error {
+			return errors.New("yaml: WithSchemaFromVersion accepts at most one argument")
+		}
+	}
+	val := len(enable) == 0 || enable[0]
+	return func(o *Options) error {
+		o.SchemaFromVersion = val
+		return nil
+	}
+}
+
 // CombineOptions combines multiple options into a single Option.
 // This is useful for creating option presets or combining version defaults
 // with custom options.
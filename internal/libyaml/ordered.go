@@ -0,0 +1,200 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Ordered mappings.
+//
+// A MapSlice holds the items of a mapping in document order. Mappings are
+// loaded into interface values as MapSlice with WithOrderedMaps, and a
+// MapSlice is dumped with its keys in the order of its items rather than
+// sorted like a Go map.
+
+package libyaml
+
+import (
+	"reflect"
+	"sort"
+)
+
+// MapItem is a key and value of a [MapSlice].
+type MapItem struct {
+	Key, Value any
+}
+
+// MapSlice is a mapping that keeps its keys in order. Iterate over it like
+// any slice; the methods find items by key.
+//
+// Keys are compared with ==, or with [reflect.DeepEqual] for keys that are
+// not comparable.
+type MapSlice []MapItem
+
+// Len returns the number of items in m.
+func (m MapSlice) Len() int {
+	return len(m)
+}
+
+// Get returns the value for key, and whether m has it.
+func (m MapSlice) Get(key any) (any, bool) {
+	if i := m.index(key); i >= 0 {
+		return m[i].Value, true
+	}
+	return nil, false
+}
+
+// Set sets the value for key. An existing key keeps its position; a new key
+// is appended.
+func (m *MapSlice) Set(key, value any) {
+	if i := m.index(key); i >= 0 {
+		(*m)[i].Value = value
+		return
+	}
+	*m = append(*m, MapItem{key, value})
+}
+
+// Delete removes key from m, and reports whether m had it.
+func (m *MapSlice) Delete(key any) bool {
+	i := m.index(key)
+	if i < 0 {
+		return false
+	}
+	*m = append((*m)[:i], (*m)[i+1:]...)
+	return true
+}
+
+// Keys returns the keys of m in order.
+func (m MapSlice) Keys() []any {
+	keys := make([]any, len(m))
+	for i, item := range m {
+		keys[i] = item.Key
+	}
+	return keys
+}
+
+// index returns the position of key in m, or -1.
+func (m MapSlice) index(key any) int {
+	for i, item := range m {
+		if sameKey(item.Key, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sameKey reports whether a and b are the same mapping key.
+func sameKey(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) {
+		return false
+	}
+	if t.Comparable() {
+		return a == b
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+var mapSliceType = reflect.TypeOf(MapSlice{})
+
+// mappingSlice constructs a MappingNode into a MapSlice, keeping the order
+// of its keys. Mappings nested in it are loaded as MapSlice too. Merged
+// keys are appended after the keys of the mapping itself.
+func (c *Constructor) mappingSlice(n *Node, out reflect.Value) (good bool) {
+	orderedMaps := c.orderedMaps
+	c.orderedMaps = true
+
+	mergedFields := c.mergedFields
+	c.mergedFields = nil
+
+	var mergeNode *Node
+
+	m := out.Interface().(MapSlice)
+	if m == nil {
+		m = MapSlice{}
+	}
+	// Index hashable keys, so that long mappings don't search the
+	// whole slice for each key.
+	index := make(map[any]int, len(m)+len(n.Content)/2)
+	for i, item := range m {
+		if item.Key == nil || reflect.TypeOf(item.Key).Comparable() {
+			index[item.Key] = i
+		}
+	}
+	for i := 0; i < len(n.Content); i += 2 {
+		if isMerge(n.Content[i]) {
+			mergeNode = n.Content[i+1]
+			continue
+		}
+		k := reflect.New(ifaceType).Elem()
+		if !c.Construct(n.Content[i], k) {
+			continue
+		}
+		ki := k.Interface()
+		if mergedFields != nil {
+			if c.getPossiblyUnhashableKey(mergedFields, ki, n.Content[i]) {
+				continue
+			}
+			c.setPossiblyUnhashableKey(mergedFields, ki, true, n.Content[i])
+		}
+		hashable := ki == nil || reflect.TypeOf(ki).Comparable()
+		pos := -1
+		if !hashable {
+			pos = m.index(ki)
+		} else if j, ok := index[ki]; ok {
+			pos = j
+		}
+		e := reflect.New(ifaceType).Elem()
+		if c.Construct(n.Content[i+1], e) || n.Content[i+1].ShortTag() == nullTag && pos < 0 {
+			if pos >= 0 {
+				m[pos].Value = e.Interface()
+				continue
+			}
+			if hashable {
+				index[ki] = len(m)
+			}
+			m = append(m, MapItem{ki, e.Interface()})
+		}
+	}
+	out.Set(reflect.ValueOf(m))
+
+	c.mergedFields = mergedFields
+	if mergeNode != nil {
+		c.merge(n, mergeNode, out)
+	}
+
+	c.orderedMaps = orderedMaps
+	return true
+}
+
+// mapSlicev represents a MapSlice as a mapping node with the keys in the
+// order of its items, unless another order was set with WithMapKeyOrder.
+func (r *Representer) mapSlicev(tag string, in MapSlice) *Node {
+	if tag == "" {
+		tag = mapTag
+	}
+	var style Style
+	if r.flow {
+		r.flow = false
+		style = FlowStyle
+	}
+
+	if r.keyOrder != nil {
+		in = append(MapSlice(nil), in...)
+		sort.SliceStable(in, func(i, j int) bool {
+			return r.keyLess(reflect.ValueOf(in[i].Key), reflect.ValueOf(in[j].Key))
+		})
+	}
+
+	content := make([]*Node, 0, len(in)*2)
+	for _, item := range in {
+		content = append(content, r.represent("", reflect.ValueOf(item.Key)))
+		content = append(content, r.represent("", reflect.ValueOf(item.Value)))
+	}
+
+	return &Node{
+		Kind:    MappingNode,
+		Tag:     tag,
+		Content: content,
+		Style:   style,
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for ordered mappings.
+
+package libyaml
+
+import (
+	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+func TestMapSlice(t *testing.T) {
+	var m MapSlice
+	m.Set("b", 1)
+	m.Set("a", 2)
+	m.Set(1, "one")
+	m.Set([]any{1}, "seq")
+	m.Set("b", 3)
+	assert.DeepEqual(t, []any{"b", "a", 1, []any{1}}, m.Keys())
+	assert.Equal(t, 4, m.Len())
+
+	v, ok := m.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, any(3), v)
+	v, ok = m.Get([]any{1})
+	assert.True(t, ok)
+	assert.Equal(t, any("seq"), v)
+	_, ok = m.Get("1")
+	assert.True(t, !ok)
+
+	assert.True(t, m.Delete("a"))
+	assert.True(t, !m.Delete("a"))
+	assert.DeepEqual(t, []any{"b", 1, []any{1}}, m.Keys())
+}
+
+func TestLoadOrderedMaps(t *testing.T) {
+	src := "z: 1\na:\n  w: [{c: 1, b: 2}]\n  x: null\n1: one\nz: 2\n"
+	var v any
+	assert.NoError(t, Load([]byte(src), &v, WithOrderedMaps(), WithUniqueKeys(false)))
+	assert.DeepEqual(t, MapSlice{
+		{"z", 2},
+		{"a", MapSlice{
+			{"w", []any{MapSlice{{"c", 1}, {"b", 2}}}},
+			{"x", nil},
+		}},
+		{1, "one"},
+	}, v)
+
+	out, err := Dump(v)
+	assert.NoError(t, err)
+	assert.Equal(t, "z: 2\na:\n  w:\n  - c: 1\n    b: 2\n  x: null\n1: one\n", string(out))
+
+	// Without the option, interface values hold Go maps.
+	assert.NoError(t, Load([]byte(src), &v, WithUniqueKeys(false)))
+	_, ok := v.(map[any]any)
+	assert.True(t, ok)
+}
+
+func TestLoadMapSliceTarget(t *testing.T) {
+	// MapSlice targets keep the order without the option, for nested
+	// mappings too.
+	var s struct {
+		M MapSlice
+		P map[string]any
+	}
+	src := "m: {b: 1, a: {d: 1, c: 2}}\np: {b: 1, a: {d: 1, c: 2}}\n"
+	assert.NoError(t, Load([]byte(src), &s))
+	assert.DeepEqual(t, MapSlice{{"b", 1}, {"a", MapSlice{{"d", 1}, {"c", 2}}}}, s.M)
+	assert.DeepEqual(t, map[string]any{"b": 1, "a": map[string]any{"d": 1, "c": 2}}, s.P)
+
+	out, err := Dump(&s)
+	assert.NoError(t, err)
+	assert.Equal(t, "m:\n  b: 1\n  a:\n    d: 1\n    c: 2\np:\n  a:\n    c: 2\n    d: 1\n  b: 1\n", string(out))
+
+	var e MapSlice
+	assert.NoError(t, Load([]byte("{}"), &e))
+	assert.DeepEqual(t, MapSlice{}, e)
+}
+
+func TestLoadOrderedMapsMerge(t *testing.T) {
+	src := "base: &base {b: 1, a: 2, c: 3}\n" +
+		"other: &other {d: 4, a: 5}\n" +
+		"m:\n  c: 0\n  <<: [*base, *other]\n  e: 6\n"
+	var v any
+	assert.NoError(t, Load([]byte(src), &v, WithOrderedMaps()))
+	m, _ := v.(MapSlice).Get("m")
+	assert.DeepEqual(t, MapSlice{{"c", 0}, {"e", 6}, {"b", 1}, {"a", 2}, {"d", 4}}, m)
+}
+
+func TestLoadOrderedMapsUniqueKeys(t *testing.T) {
+	var v any
+	err := Load([]byte("a: 1\nb: 2\na: 3\n"), &v, WithOrderedMaps(), WithUniqueKeys())
+	assert.ErrorMatches(t, `yaml: construct errors: line 3: mapping key "a" already defined at line 1`, err)
+}
+
+func TestDumpMapSliceFlow(t *testing.T) {
+	v := struct {
+		M MapSlice `yaml:"m,flow"`
+	}{MapSlice{{"b", 1}, {"a", []int{1, 2}}}}
+	out, err := Dump(&v)
+	assert.NoError(t, err)
+	assert.Equal(t, "m: {b: 1, a: [1, 2]}\n", string(out))
+}
@@ -0,0 +1,434 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Layering of YAML documents.
+//
+// Merge applies an overlay document on top of a base document and returns
+// a new tree; neither input is modified. Mappings are merged key by key,
+// everything else is replaced by the overlay, unless a rule selects another
+// strategy for a path. An overlay value tagged !reset replaces the base
+// value without merging, and an empty !reset value deletes the key.
+
+package libyaml
+
+import (
+	"errors"
+	"fmt"
+)
+
+// resetTag marks overlay values that replace or delete the base value.
+const resetTag = "!reset"
+
+// MergeStrategy selects how [Merge] combines a base value with an overlay
+// value at the same location.
+type MergeStrategy int
+
+const (
+	// MergeDeep merges mappings key by key, recursively. Sequences and
+	// scalars are replaced. It is the default strategy.
+	MergeDeep MergeStrategy = iota
+
+	// MergeReplace replaces the base value with the overlay value.
+	MergeReplace
+
+	// MergeAppend appends overlay sequence items to the base items.
+	MergeAppend
+
+	// MergePrepend inserts overlay sequence items before the base items.
+	MergePrepend
+
+	// MergeByKey matches sequence items that are mappings by the value of
+	// a key field and merges matching items. Unmatched overlay items are
+	// appended. Set it with [MergeKey].
+	MergeByKey
+)
+
+// MergeOption configures [Merge].
+type MergeOption func(*mergeOptions) error
+
+type mergeOptions struct {
+	rules []mergeRule
+}
+
+// mergeRule applies a strategy to the nodes selected by a path.
+type mergeRule struct {
+	path     *Path
+	strategy MergeStrategy
+	key      string
+}
+
+// MergeRule makes [Merge] use strategy for the values selected by the path
+// expression in either input. Later rules take precedence over earlier
+// ones for the same value.
+//
+// For example, to accumulate rather than replace a list of arguments:
+//
+//	Merge(base, overlay, MergeRule("spec.args", MergeAppend))
+func MergeRule(path string, strategy MergeStrategy) MergeOption {
+	return func(o *mergeOptions) error {
+		if strategy == MergeByKey {
+			return errors.New("yaml: MergeByKey needs a key field; use MergeKey")
+		}
+		if strategy < MergeDeep || strategy > MergeByKey {
+			return fmt.Errorf("yaml: unknown merge strategy %d", strategy)
+		}
+		p, err := CompilePath(path)
+		if err != nil {
+			return err
+		}
+		o.rules = append(o.rules, mergeRule{path: p, strategy: strategy})
+		return nil
+	}
+}
+
+// MergeKey makes [Merge] combine the sequences selected by the path
+// expression with [MergeByKey], matching items on the value of key.
+//
+// An overlay item tagged !reset replaces the matching base item instead
+// of merging into it; if it holds nothing but the key, the base item is
+// deleted.
+func MergeKey(path, key string) MergeOption {
+	return func(o *mergeOptions) error {
+		if key == "" {
+			return errors.New("yaml: MergeKey needs a non-empty key")
+		}
+		p, err := CompilePath(path)
+		if err != nil {
+			return err
+		}
+		o.rules = append(o.rules, mergeRule{path: p, strategy: MergeByKey, key: key})
+		return nil
+	}
+}
+
+// Merge returns a new tree holding overlay applied on top of base.
+//
+// Comments from both inputs are kept: values taken from the overlay keep
+// their own comments, and inherit the base comments when they have none.
+// Aliases in the overlay are expanded in the result, and so are base
+// aliases whose target is merged into. When base is a DocumentNode the
+// result is a DocumentNode too, ready to be written by a Dumper.
+func Merge(base, overlay *Node, opts ...MergeOption) (result *Node, err error) {
+	defer handleErr(&err)
+	if base == nil || overlay == nil {
+		return nil, errors.New("yaml: cannot merge a nil node")
+	}
+	var o mergeOptions
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+	m := &merger{rules: make(map[*Node]mergeRule), copies: make(map[*Node]*Node)}
+	for _, rule := range o.rules {
+		for _, root := range []*Node{base, overlay} {
+			nodes, err := rule.path.Find(root)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range nodes {
+				m.rules[n] = rule
+			}
+		}
+	}
+
+	b, ov := queryRoot(base), queryRoot(overlay)
+	var content *Node
+	switch {
+	case ov == nil && b == nil:
+		content = nil
+	case ov == nil:
+		content = cloneNode(b)
+	default:
+		content = m.merge(b, ov)
+		m.relinkAliases(content)
+	}
+	if base.Kind != DocumentNode {
+		if content == nil {
+			return &Node{Kind: ScalarNode, Tag: nullTag, Value: "null"}, nil
+		}
+		return content, nil
+	}
+	doc := &Node{
+		Kind:        DocumentNode,
+		HeadComment: base.HeadComment,
+		LineComment: base.LineComment,
+		FootComment: base.FootComment,
+	}
+	if overlay.Kind == DocumentNode {
+		mergeComments(doc, overlay)
+	}
+	if content != nil {
+		doc.Content = []*Node{content}
+	}
+	return doc, nil
+}
+
+// merger holds the state of a single Merge call.
+type merger struct {
+	rules  map[*Node]mergeRule // Strategy per input node
+	copies map[*Node]*Node     // Node standing in for each base node in the result
+}
+
+// rule returns the rule applying to a location, preferring a rule matched
+// in the overlay.
+func (m *merger) rule(base, overlay *Node) mergeRule {
+	if r, ok := m.rules[overlay]; ok {
+		return r
+	}
+	if r, ok := m.rules[base]; ok {
+		return r
+	}
+	return mergeRule{strategy: MergeDeep}
+}
+
+// merge combines base (which may be nil) and overlay into a new node.
+// The result keeps the anchor of base so that aliases to it stay valid,
+// unless base was itself reached through an alias.
+func (m *merger) merge(base, overlay *Node) *Node {
+	if base == nil {
+		return copyOverlay(overlay)
+	}
+	anchor := ""
+	if base.Kind != AliasNode {
+		anchor = base.Anchor
+	}
+	result := m.mergeValue(derefAlias(base), derefAlias(overlay))
+	result.Anchor = anchor
+	if base.Kind != AliasNode {
+		m.copies[base] = result
+	}
+	return result
+}
+
+// mergeValue combines base and overlay with the strategy that applies to
+// them.
+func (m *merger) mergeValue(base, overlay *Node) *Node {
+	rule := m.rule(base, overlay)
+	if overlay.Tag == resetTag || rule.strategy == MergeReplace || base.Kind != overlay.Kind {
+		return replaceValue(base, overlay)
+	}
+	var result *Node
+	switch overlay.Kind {
+	case MappingNode:
+		result = m.mergeMapping(base, overlay)
+	case SequenceNode:
+		switch rule.strategy {
+		case MergeAppend:
+			result = m.copyBase(base)
+			for _, item := range overlay.Content {
+				result.insertItem(len(result.Content), copyOverlay(item))
+			}
+		case MergePrepend:
+			result = m.copyBase(base)
+			for i, item := range overlay.Content {
+				result.insertItem(i, copyOverlay(item))
+			}
+		case MergeByKey:
+			result = m.mergeByKey(base, overlay, rule.key)
+		default:
+			return replaceValue(base, overlay)
+		}
+	default:
+		return replaceValue(base, overlay)
+	}
+	mergeComments(result, overlay)
+	if overlay.Style&FlowStyle != 0 {
+		result.Style |= FlowStyle
+	}
+	return result
+}
+
+// mergeMapping merges the entries of overlay into a copy of base.
+func (m *merger) mergeMapping(base, overlay *Node) *Node {
+	result := m.copyBase(base)
+	entries := mappingEntries(overlay)
+	for i := 0; i+1 < len(entries); i += 2 {
+		key, value := derefAlias(entries[i]), derefAlias(entries[i+1])
+		if key.Kind != ScalarNode {
+			result.insertPair(len(result.Content)/2, copyOverlay(key), copyOverlay(value))
+			continue
+		}
+		j := result.keyIndex(key.Value)
+		if isDeletion(value) {
+			if j >= 0 {
+				result.removePair(j / 2)
+			}
+			continue
+		}
+		if j >= 0 {
+			// Merge with the original base value, which carries the rules
+			// for this location; result only holds a copy of it.
+			prev := result.Content[j+1]
+			if k := base.keyIndex(key.Value); k >= 0 {
+				prev = base.Content[k+1]
+			}
+			mergeComments(result.Content[j], key)
+			result.Content[j+1] = m.merge(prev, value)
+			continue
+		}
+		// A key that base only has through a << merge is merged with the
+		// inherited value and added as a direct key.
+		var inherited *Node
+		baseEntries := mappingEntries(base)
+		for k := 0; k+1 < len(baseEntries); k += 2 {
+			if bk := derefAlias(baseEntries[k]); bk.Kind == ScalarNode && bk.Value == key.Value {
+				inherited = baseEntries[k+1]
+				break
+			}
+		}
+		result.insertPair(len(result.Content)/2, copyOverlay(key), m.merge(inherited, value))
+	}
+	return result
+}
+
+// mergeByKey merges two sequences of mappings, matching items on the value
+// of key.
+func (m *merger) mergeByKey(base, overlay *Node, key string) *Node {
+	result := m.copyBase(base)
+	// Track result items by their original base item, as deletions shift
+	// indexes in the result.
+	origin := make(map[*Node]*Node, len(base.Content))
+	for i, item := range base.Content {
+		origin[result.Content[i]] = item
+	}
+	for _, item := range overlay.Content {
+		item = derefAlias(item)
+		id := itemKey(item, key)
+		pos := -1
+		if id != nil {
+			for i, r := range result.Content {
+				if k := itemKey(origin[r], key); k != nil && k.Value == id.Value {
+					pos = i
+					break
+				}
+			}
+		}
+		switch {
+		case pos < 0:
+			result.insertItem(len(result.Content), copyOverlay(item))
+		case item.Tag == resetTag && len(mappingEntries(item)) == 2:
+			result.removeItem(pos)
+		default:
+			merged := m.merge(origin[result.Content[pos]], item)
+			origin[merged] = origin[result.Content[pos]]
+			result.Content[pos] = merged
+		}
+	}
+	return result
+}
+
+// itemKey returns the scalar value node of key in a mapping item, or nil.
+func itemKey(item *Node, key string) *Node {
+	item = derefAlias(item)
+	if item == nil || item.Kind != MappingNode {
+		return nil
+	}
+	entries := mappingEntries(item)
+	for i := 0; i+1 < len(entries); i += 2 {
+		k, v := derefAlias(entries[i]), derefAlias(entries[i+1])
+		if k.Kind == ScalarNode && k.Value == key && v.Kind == ScalarNode {
+			return v
+		}
+	}
+	return nil
+}
+
+// isDeletion reports whether an overlay value deletes its key: an empty or
+// null scalar tagged !reset.
+func isDeletion(n *Node) bool {
+	if n.Tag != resetTag || n.Kind != ScalarNode {
+		return false
+	}
+	tag, _ := resolve("", n.Value)
+	return tag == nullTag
+}
+
+// replaceValue returns a copy of overlay to stand in for base, inheriting
+// the base comments when the overlay value has none.
+func replaceValue(base, overlay *Node) *Node {
+	result := copyOverlay(overlay)
+	inheritDecorations(result, base)
+	return result
+}
+
+// copyBase returns a copy of a base collection whose entries can be
+// replaced without affecting the input. Untouched entries are deep copies
+// that keep their anchors and aliases. Every copy is recorded in m.copies,
+// so that relinkAliases can point aliases at the copies.
+func (m *merger) copyBase(n *Node) *Node {
+	c := *n
+	m.copies[n] = &c
+	c.Content = make([]*Node, len(n.Content))
+	for i, child := range n.Content {
+		c.Content[i] = cloneInto(child, m.copies)
+	}
+	return &c
+}
+
+// relinkAliases points the aliases copied from base into the result tree at
+// the nodes standing in for their targets, so that the result does not
+// share nodes with base. Aliases whose target did not make it into the
+// result are expanded.
+func (m *merger) relinkAliases(result *Node) {
+	inResult := make(map[*Node]bool)
+	var aliases []*Node
+	var collect func(n *Node)
+	collect = func(n *Node) {
+		if inResult[n] {
+			return
+		}
+		inResult[n] = true
+		if n.Kind == AliasNode {
+			aliases = append(aliases, n)
+		}
+		for _, child := range n.Content {
+			collect(child)
+		}
+	}
+	collect(result)
+	for _, a := range aliases {
+		if inResult[a.Alias] {
+			continue
+		}
+		if c, ok := m.copies[a.Alias]; ok && inResult[c] {
+			a.Alias = c
+			continue
+		}
+		expanded := detachedCopy(a)
+		inheritDecorations(expanded, a)
+		*a = *expanded
+	}
+}
+
+// copyOverlay returns a detached copy of an overlay node with !reset tags
+// stripped, so it can be placed anywhere in the result.
+func copyOverlay(n *Node) *Node {
+	c := detachedCopy(n)
+	stripResetTags(c)
+	return c
+}
+
+// stripResetTags removes !reset tags from a node tree.
+func stripResetTags(n *Node) {
+	if n.Tag == resetTag {
+		n.Tag = ""
+		n.Style &^= TaggedStyle
+	}
+	for _, c := range n.Content {
+		stripResetTags(c)
+	}
+}
+
+// mergeComments copies the comments that are set on src over those on dst.
+func mergeComments(dst, src *Node) {
+	if src.HeadComment != "" {
+		dst.HeadComment = src.HeadComment
+	}
+	if src.LineComment != "" {
+		dst.LineComment = src.LineComment
+	}
+	if src.FootComment != "" {
+		dst.FootComment = src.FootComment
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for layering YAML documents with Merge.
+
+package libyaml
+
+import (
+	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+// mergeDocs loads base and overlay, merges them and dumps the result.
+func mergeDocs(t *testing.T, base, overlay string, opts ...MergeOption) string {
+	t.Helper()
+	var b, o Node
+	assert.NoError(t, Load([]byte(base), &b))
+	assert.NoError(t, Load([]byte(overlay), &o))
+	before, err := Dump(&b)
+	assert.NoError(t, err)
+	result, err := Merge(&b, &o, opts...)
+	assert.NoError(t, err)
+	out, err := Dump(result)
+	assert.NoError(t, err)
+	after, err := Dump(&b)
+	assert.NoError(t, err)
+	assert.Equalf(t, string(before), string(after), "Merge modified its base input")
+	return string(out)
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name          string
+		base, overlay string
+		opts          []MergeOption
+		want          string
+	}{{
+		name:    "deep merge keeps comments from both sides",
+		base:    "# base config\nname: app # app name\nspec:\n  replicas: 1 # scaled by overlay\n  image: nginx\n",
+		overlay: "spec:\n  replicas: 3\n  # pull policy for prod\n  pull: Always\n",
+		want:    "# base config\nname: app # app name\nspec:\n  replicas: 3 # scaled by overlay\n  image: nginx\n  # pull policy for prod\n  pull: Always\n",
+	}, {
+		name:    "overlay comment wins",
+		base:    "a: 1 # old\n",
+		overlay: "a: 2 # new\n",
+		want:    "a: 2 # new\n",
+	}, {
+		name:    "sequences replaced by default",
+		base:    "args: [a, b]\n",
+		overlay: "args: [c]\n",
+		want:    "args: [c]\n",
+	}, {
+		name:    "append",
+		base:    "args:\n- a\n- b\n",
+		overlay: "args:\n- c\n",
+		opts:    []MergeOption{MergeRule("args", MergeAppend)},
+		want:    "args:\n- a\n- b\n- c\n",
+	}, {
+		name:    "prepend with recursive rule",
+		base:    "x:\n  args: [a, b]\n",
+		overlay: "x:\n  args: [c, d]\n",
+		opts:    []MergeOption{MergeRule("$..args", MergePrepend)},
+		want:    "x:\n  args: [c, d, a, b]\n",
+	}, {
+		name:    "replace mapping",
+		base:    "env: {A: 1, B: 2}\n",
+		overlay: "env: {C: 3}\n",
+		opts:    []MergeOption{MergeRule("env", MergeReplace)},
+		want:    "env: {C: 3}\n",
+	}, {
+		name:    "reset tag replaces",
+		base:    "env: {A: 1, B: 2}\n",
+		overlay: "env: !reset {C: 3}\n",
+		want:    "env: {C: 3}\n",
+	}, {
+		name:    "reset tag deletes",
+		base:    "a: 1\nb: 2\nc: 3\n",
+		overlay: "b: !reset\n",
+		want:    "a: 1\nc: 3\n",
+	}, {
+		name: "merge by key",
+		base: `containers:
+- name: web
+  image: nginx:1
+  ports: [80]
+- name: sidecar
+  image: envoy
+- name: debug
+  image: busybox
+`,
+		overlay: `containers:
+- name: web
+  image: nginx:2
+- !reset {name: sidecar}
+- !reset {name: debug, image: alpine}
+- name: metrics
+  image: prom
+`,
+		opts: []MergeOption{MergeKey("containers", "name")},
+		want: `containers:
+- name: web
+  image: nginx:2
+  ports: [80]
+- {name: debug, image: alpine}
+- name: metrics
+  image: prom
+`,
+	}, {
+		name:    "base anchors stay valid",
+		base:    "defaults: &d {timeout: 1}\nsvc: *d\n",
+		overlay: "defaults: {timeout: 2}\n",
+		want:    "defaults: &d {timeout: 2}\nsvc: *d\n",
+	}, {
+		name:    "merging into an alias expands it",
+		base:    "defaults: &d {timeout: 1}\nsvc: *d\n",
+		overlay: "svc: {retries: 3}\n",
+		want:    "defaults: &d {timeout: 1}\nsvc: {timeout: 1, retries: 3}\n",
+	}, {
+		name:    "overlay aliases expanded",
+		base:    "a: 1\n",
+		overlay: "x: &x {y: 1}\nz: *x\n",
+		want:    "a: 1\nx: {y: 1}\nz: {y: 1}\n",
+	}, {
+		name:    "inherited merge key value is deep merged",
+		base:    "d: &d\n  opts: {a: 1}\nsvc:\n  <<: *d\n",
+		overlay: "svc:\n  opts: {b: 2}\n",
+		want:    "d: &d\n  opts: {a: 1}\nsvc:\n  <<: *d\n  opts: {a: 1, b: 2}\n",
+	}, {
+		name:    "kind change replaces",
+		base:    "a: {x: 1}\n",
+		overlay: "a: [1]\n",
+		want:    "a: [1]\n",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, mergeDocs(t, tc.base, tc.overlay, tc.opts...))
+		})
+	}
+}
+
+func TestMergeAliases(t *testing.T) {
+	var b, o Node
+	assert.NoError(t, Load([]byte("a: &a {p: 1}\nb: *a\nc: &c 1\nd: *c\ne: &e [1]\nf: *e\n"), &b))
+	assert.NoError(t, Load([]byte("a: {q: 2}\nc: !reset\n"), &o))
+	before, err := Dump(&b)
+	assert.NoError(t, err)
+	result, err := Merge(&b, &o)
+	assert.NoError(t, err)
+
+	// Aliases point at the nodes in the result, and aliases to dropped
+	// nodes are expanded.
+	m := result.Content[0]
+	assert.True(t, m.Content[3].Alias == m.Content[1])
+	assert.True(t, m.Content[9].Alias == m.Content[7])
+	out, err := Dump(result)
+	assert.NoError(t, err)
+	assert.Equal(t, "a: &a {p: 1, q: 2}\nb: *a\nd: 1\ne: &e [1]\nf: *e\n", string(out))
+
+	// Editing the result through an alias leaves base alone.
+	assert.NoError(t, m.Content[9].Alias.InsertIndex(1, &Node{Kind: ScalarNode, Value: "2"}))
+	after, err := Dump(&b)
+	assert.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+}
+
+func TestMergeErrors(t *testing.T) {
+	var n Node
+	assert.NoError(t, Load([]byte("a: 1\n"), &n))
+	_, err := Merge(&n, nil)
+	assert.ErrorMatches(t, `yaml: cannot merge a nil node`, err)
+	_, err = Merge(&n, &n, MergeRule("a", MergeByKey))
+	assert.ErrorMatches(t, `yaml: MergeByKey needs a key field; use MergeKey`, err)
+	_, err = Merge(&n, &n, MergeKey("a", ""))
+	assert.ErrorMatches(t, `yaml: MergeKey needs a non-empty key`, err)
+	_, err = Merge(&n, &n, MergeRule("a[", MergeAppend))
+	assert.ErrorMatches(t, `yaml: invalid path .*`, err)
+}
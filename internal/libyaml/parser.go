@@ -238,7 +238,9 @@ type Parser struct {
 	simple_key          SimpleKey   // The current simple key.
 	simple_key_stack    []SimpleKey // The stack of simple keys.
 
-	depthCheck func(int, *DepthContext) error // Depth limit check function
+	depthCheck  func(int, *DepthContext) error // Depth limit check function
+	limits      ResourceLimits                 // Input size and scalar length limits
+	inputOffset int                            // Input counted against limits.InputBytes before this one
 
 	// Parser stuff
 
@@ -635,7 +637,7 @@ func (parser *Parser) processDirectives(version_directive_ref **VersionDirective
 				return formatParserError(
 					"found duplicate %YAML directive", token.StartMark)
 			}
-			if token.major != 1 || token.minor != 1 {
+			if token.major != 1 || (token.minor != 1 && token.minor != 2) {
 				return formatParserError(
 					"found incompatible YAML document", token.StartMark)
 			}
@@ -0,0 +1,680 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// JSON Patch (RFC 6902) and JSON Merge Patch (RFC 7386) over Node trees.
+//
+// Patches are applied to the node tree in place, through the editing
+// methods in edit.go, so comments and styles of untouched content survive.
+// Locations are addressed with JSON Pointers (RFC 6901). Patch documents are
+// read with this package, so they may be written in JSON or YAML.
+
+package libyaml
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch document to the tree rooted
+// at doc. The patch is applied atomically: if any operation fails, doc is
+// left unchanged and the error names the failing operation.
+//
+// Values inserted by the patch are given block style, so that patching a
+// block-style document does not introduce JSON-style flow collections.
+func ApplyJSONPatch(doc *Node, patch []byte) (err error) {
+	defer handleErr(&err)
+	var p Node
+	if err := Load(patch, &p); err != nil {
+		return fmt.Errorf("yaml: invalid json patch: %w", err)
+	}
+	ops := queryRoot(&p)
+	if ops == nil || ops.Kind != SequenceNode {
+		return errors.New("yaml: invalid json patch: expected a sequence of operations")
+	}
+	return patchInPlace(doc, func(t *patchTarget) error {
+		for i, op := range ops.Content {
+			if err := t.apply(op); err != nil {
+				return fmt.Errorf("yaml: json patch operation %d: %w", i, err)
+			}
+		}
+		return nil
+	})
+}
+
+// ApplyMergePatch applies an RFC 7386 JSON Merge Patch document to the tree
+// rooted at doc: mappings in the patch are merged into the target key by
+// key, null values delete keys, and any other value replaces the target.
+func ApplyMergePatch(doc *Node, patch []byte) (err error) {
+	defer handleErr(&err)
+	var p Node
+	if err := Load(patch, &p); err != nil {
+		return fmt.Errorf("yaml: invalid merge patch: %w", err)
+	}
+	return patchInPlace(doc, func(t *patchTarget) error {
+		t.root = mergePatch(t.root, queryRoot(&p))
+		return nil
+	})
+}
+
+// CreatePatch returns an RFC 6902 JSON Patch document, encoded as JSON, that
+// turns a into b when applied with [ApplyJSONPatch]. Values are compared by
+// resolved tag and value, as in [Diff]. It fails if either tree holds a
+// mapping key that is not a string, or a value that cannot be encoded as
+// JSON.
+func CreatePatch(a, b *Node) (out []byte, err error) {
+	defer handleErr(&err)
+	g := &patchGenerator{}
+	if err := g.diff("", queryRoot(a), queryRoot(b)); err != nil {
+		return nil, err
+	}
+	if g.ops == nil {
+		g.ops = []patchOp{}
+	}
+	return json.Marshal(g.ops)
+}
+
+// patchInPlace runs apply against a copy of the content of doc and, if it
+// succeeds, installs the result in doc.
+func patchInPlace(doc *Node, apply func(t *patchTarget) error) error {
+	if doc == nil {
+		return errors.New("yaml: cannot patch a nil node")
+	}
+	root := doc
+	if doc.Kind == DocumentNode {
+		root = nil
+		if len(doc.Content) > 0 {
+			root = doc.Content[0]
+		}
+	}
+	t := &patchTarget{root: cloneNode(root)}
+	if err := apply(t); err != nil {
+		return err
+	}
+	switch {
+	case doc.Kind == DocumentNode && t.root == nil:
+		doc.Content = nil
+	case doc.Kind == DocumentNode:
+		doc.Content = []*Node{t.root}
+	case t.root == nil:
+		*doc = Node{Kind: ScalarNode, Tag: nullTag, Value: "null"}
+	default:
+		*doc = *t.root
+	}
+	return nil
+}
+
+// --------------------------------------------------------------------------
+// JSON Pointer
+
+// parsePointer splits an RFC 6901 JSON Pointer into unescaped reference
+// tokens. The empty pointer refers to the whole document.
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with '/'", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, tok := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// appendPointer returns ptr extended by an escaped reference token.
+func appendPointer(ptr, token string) string {
+	return ptr + "/" + strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// pointerIndex parses a sequence index token. RFC 6901 forbids signs and
+// leading zeros.
+func pointerIndex(tok string) (int, error) {
+	if tok == "" || len(tok) > 1 && tok[0] == '0' || strings.TrimLeft(tok, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", tok)
+	}
+	return strconv.Atoi(tok)
+}
+
+// --------------------------------------------------------------------------
+// Applying patches
+
+// patchTarget is the tree being patched.
+type patchTarget struct {
+	root *Node
+}
+
+// apply applies a single patch operation.
+func (t *patchTarget) apply(op *Node) error {
+	op = derefAlias(op)
+	if op == nil || op.Kind != MappingNode {
+		return errors.New("operation must be a mapping")
+	}
+	fields := make(map[string]*Node)
+	entries := mappingEntries(op)
+	for i := 0; i+1 < len(entries); i += 2 {
+		fields[derefAlias(entries[i]).Value] = derefAlias(entries[i+1])
+	}
+	str := func(name string) (string, error) {
+		f, ok := fields[name]
+		if !ok || f.Kind != ScalarNode || f.ShortTag() != strTag {
+			return "", fmt.Errorf("missing string member %q", name)
+		}
+		return f.Value, nil
+	}
+	name, err := str("op")
+	if err != nil {
+		return err
+	}
+	path, err := str("path")
+	if err != nil {
+		return err
+	}
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return err
+	}
+	value := func() (*Node, error) {
+		v, ok := fields["value"]
+		if !ok {
+			return nil, fmt.Errorf("%s operation requires a value", name)
+		}
+		return patchValue(v), nil
+	}
+	from := func() ([]string, error) {
+		f, err := str("from")
+		if err != nil {
+			return nil, err
+		}
+		return parsePointer(f)
+	}
+
+	switch name {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return err
+		}
+		return t.add(tokens, v)
+	case "remove":
+		_, err := t.remove(tokens)
+		return err
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return err
+		}
+		return t.replace(tokens, v)
+	case "move":
+		src, err := from()
+		if err != nil {
+			return err
+		}
+		if isPointerPrefix(src, tokens) && len(src) < len(tokens) {
+			return errors.New("cannot move a value into itself")
+		}
+		v, err := t.remove(src)
+		if err != nil {
+			return err
+		}
+		return t.add(tokens, v)
+	case "copy":
+		src, err := from()
+		if err != nil {
+			return err
+		}
+		v, err := t.get(src)
+		if err != nil {
+			return err
+		}
+		// The copy does not take the comments describing the original.
+		return t.add(tokens, displayCopy(v, make(map[*Node]bool)))
+	case "test":
+		v, err := value()
+		if err != nil {
+			return err
+		}
+		actual, err := t.get(tokens)
+		if err != nil {
+			return err
+		}
+		if !jsonEqual(actual, v) {
+			return fmt.Errorf("test failed: value at %q differs", path)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown operation %q", name)
+}
+
+// isPointerPrefix reports whether prefix is a prefix of tokens.
+func isPointerPrefix(prefix, tokens []string) bool {
+	if len(prefix) > len(tokens) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != tokens[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// patchValue prepares a value from a patch document for insertion: it is
+// detached from the patch tree and stripped of its JSON formatting and
+// source positions.
+func patchValue(n *Node) *Node {
+	c := detachedCopy(n)
+	var normalize func(n *Node)
+	normalize = func(n *Node) {
+		n.Style = 0
+		n.Line, n.Column = 0, 0
+		for _, child := range n.Content {
+			normalize(child)
+		}
+	}
+	normalize(c)
+	return c
+}
+
+// get returns the node referenced by tokens, following aliases and merge
+// keys.
+func (t *patchTarget) get(tokens []string) (*Node, error) {
+	n := t.root
+	if n == nil {
+		return nil, fmt.Errorf("%w: document is empty", ErrNotFound)
+	}
+	for i, tok := range tokens {
+		n = derefAlias(n)
+		switch n.Kind {
+		case MappingNode:
+			var found *Node
+			entries := mappingEntries(n)
+			for j := 0; j+1 < len(entries); j += 2 {
+				if k := derefAlias(entries[j]); k.Kind == ScalarNode && k.Value == tok {
+					found = entries[j+1]
+					break
+				}
+			}
+			if found == nil {
+				return nil, fmt.Errorf("%w: %s", ErrNotFound, pointerString(tokens[:i+1]))
+			}
+			n = found
+		case SequenceNode:
+			idx, err := pointerIndex(tok)
+			if err != nil {
+				return nil, err
+			}
+			if idx >= len(n.Content) {
+				return nil, fmt.Errorf("%w: %s", ErrNotFound, pointerString(tokens[:i+1]))
+			}
+			n = n.Content[idx]
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, pointerString(tokens[:i+1]))
+		}
+	}
+	return derefAlias(n), nil
+}
+
+// parent returns the collection holding the location referenced by tokens,
+// prepared for modification: aliases on the way are replaced by copies of
+// their targets, so that edits do not leak to other references to the same
+// anchor, and keys inherited through << merge keys become direct keys.
+func (t *patchTarget) parent(tokens []string) (*Node, error) {
+	n := t.root
+	if n == nil {
+		return nil, fmt.Errorf("%w: document is empty", ErrNotFound)
+	}
+	if n.Kind == AliasNode {
+		n = detachedCopy(n)
+		t.root = n
+	}
+	for i, tok := range tokens[:len(tokens)-1] {
+		var slot **Node
+		switch n.Kind {
+		case MappingNode:
+			j := n.keyIndex(tok)
+			if j < 0 {
+				inherited, err := t.get(tokens[:i+1])
+				if err != nil {
+					return nil, err
+				}
+				n.insertPair(len(n.Content)/2, newKeyNode(tok), detachedCopy(inherited))
+				j = len(n.Content) - 2
+			}
+			slot = &n.Content[j+1]
+		case SequenceNode:
+			idx, err := pointerIndex(tok)
+			if err != nil {
+				return nil, err
+			}
+			if idx >= len(n.Content) {
+				return nil, fmt.Errorf("%w: %s", ErrNotFound, pointerString(tokens[:i+1]))
+			}
+			slot = &n.Content[idx]
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, pointerString(tokens[:i+1]))
+		}
+		if (*slot).Kind == AliasNode {
+			*slot = detachedCopy(*slot)
+		}
+		n = *slot
+	}
+	return n, nil
+}
+
+// pointerString formats tokens as a JSON Pointer.
+func pointerString(tokens []string) string {
+	var b strings.Builder
+	for _, tok := range tokens {
+		b.WriteString(appendPointer("", tok))
+	}
+	return b.String()
+}
+
+func (t *patchTarget) add(tokens []string, value *Node) error {
+	if len(tokens) == 0 {
+		t.root = value
+		return nil
+	}
+	parent, err := t.parent(tokens)
+	if err != nil {
+		return err
+	}
+	last := tokens[len(tokens)-1]
+	switch parent.Kind {
+	case MappingNode:
+		return parent.SetKey(last, value)
+	case SequenceNode:
+		if last == "-" {
+			return parent.InsertIndex(len(parent.Content), value)
+		}
+		idx, err := pointerIndex(last)
+		if err != nil {
+			return err
+		}
+		return parent.InsertIndex(idx, value)
+	}
+	return fmt.Errorf("%w: %s", ErrNotFound, pointerString(tokens))
+}
+
+func (t *patchTarget) remove(tokens []string) (*Node, error) {
+	if len(tokens) == 0 {
+		v := t.root
+		t.root = nil
+		return v, nil
+	}
+	parent, err := t.parent(tokens)
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch parent.Kind {
+	case MappingNode:
+		j := parent.keyIndex(last)
+		if j < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, pointerString(tokens))
+		}
+		_, v := parent.removePair(j / 2)
+		return v, nil
+	case SequenceNode:
+		idx, err := pointerIndex(last)
+		if err != nil {
+			return nil, err
+		}
+		if idx >= len(parent.Content) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, pointerString(tokens))
+		}
+		return parent.removeItem(idx), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, pointerString(tokens))
+}
+
+func (t *patchTarget) replace(tokens []string, value *Node) error {
+	if len(tokens) == 0 {
+		if t.root != nil {
+			inheritDecorations(value, t.root)
+		}
+		t.root = value
+		return nil
+	}
+	if _, err := t.get(tokens); err != nil {
+		return err
+	}
+	parent, err := t.parent(tokens)
+	if err != nil {
+		return err
+	}
+	last := tokens[len(tokens)-1]
+	if parent.Kind == SequenceNode {
+		idx, err := pointerIndex(last)
+		if err != nil {
+			return err
+		}
+		return parent.SetIndex(idx, value)
+	}
+	return parent.SetKey(last, value)
+}
+
+// mergePatch applies an RFC 7386 merge patch to target and returns the
+// result. target may be nil.
+func mergePatch(target, patch *Node) *Node {
+	patch = derefAlias(patch)
+	if patch == nil {
+		return target
+	}
+	if patch.Kind != MappingNode {
+		value := patchValue(patch)
+		if target != nil {
+			inheritDecorations(value, target)
+		}
+		return value
+	}
+	target = derefAlias(target)
+	if target == nil || target.Kind != MappingNode {
+		target = &Node{Kind: MappingNode, Tag: mapTag}
+	}
+	entries := mappingEntries(patch)
+	for i := 0; i+1 < len(entries); i += 2 {
+		key, value := derefAlias(entries[i]).Value, derefAlias(entries[i+1])
+		if value.Kind == ScalarNode && value.ShortTag() == nullTag {
+			if j := target.keyIndex(key); j >= 0 {
+				target.removePair(j / 2)
+			}
+			continue
+		}
+		if j := target.keyIndex(key); j >= 0 {
+			current := target.Content[j+1]
+			if current.Kind == AliasNode {
+				current = detachedCopy(current)
+			}
+			target.Content[j+1] = mergePatch(current, value)
+			continue
+		}
+		target.insertPair(len(target.Content)/2, newKeyNode(key), mergePatch(nil, value))
+	}
+	return target
+}
+
+// jsonEqual reports whether two nodes hold equal JSON values. Unlike
+// [Diff], integers and floats with the same numeric value are equal, as
+// RFC 6902 requires for the test operation.
+func jsonEqual(a, b *Node) bool {
+	a, b = derefAlias(a), derefAlias(b)
+	if a == nil || b == nil || a.Kind != b.Kind {
+		return a == nil && b == nil
+	}
+	switch a.Kind {
+	case ScalarNode:
+		ta, va := resolveScalar(a)
+		tb, vb := resolveScalar(b)
+		if (ta == intTag || ta == floatTag) && (tb == intTag || tb == floatTag) {
+			return valuesEqual(va, vb)
+		}
+		return scalarsEqual(a, b)
+	case SequenceNode:
+		if len(a.Content) != len(b.Content) {
+			return false
+		}
+		for i := range a.Content {
+			if !jsonEqual(a.Content[i], b.Content[i]) {
+				return false
+			}
+		}
+		return true
+	case MappingNode:
+		ea, eb := mappingEntries(a), mappingEntries(b)
+		if len(ea) != len(eb) {
+			return false
+		}
+		values := make(map[string]*Node, len(eb)/2)
+		for i := 0; i+1 < len(eb); i += 2 {
+			values[fingerprint(eb[i])] = eb[i+1]
+		}
+		for i := 0; i+1 < len(ea); i += 2 {
+			v, ok := values[fingerprint(ea[i])]
+			if !ok || !jsonEqual(ea[i+1], v) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// --------------------------------------------------------------------------
+// Creating patches
+
+// patchOp is a single RFC 6902 operation.
+type patchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// patchGenerator accumulates the operations turning one tree into another.
+type patchGenerator struct {
+	ops []patchOp
+}
+
+func (g *patchGenerator) emit(op, path string, value *Node) error {
+	o := patchOp{Op: op, Path: path}
+	if value != nil {
+		var v any
+		if err := value.Load(&v); err != nil {
+			return err
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("yaml: cannot encode value at %q as JSON: %w", path, err)
+		}
+		o.Value = data
+	}
+	g.ops = append(g.ops, o)
+	return nil
+}
+
+// diff emits operations turning a into b at the location ptr.
+func (g *patchGenerator) diff(ptr string, a, b *Node) error {
+	a, b = derefAlias(a), derefAlias(b)
+	switch {
+	case a == nil && b == nil:
+		return nil
+	case a == nil:
+		return g.emit("add", ptr, b)
+	case b == nil:
+		return g.emit("remove", ptr, nil)
+	case a.Kind != b.Kind:
+		return g.emit("replace", ptr, b)
+	}
+	switch a.Kind {
+	case ScalarNode:
+		if !scalarsEqual(a, b) {
+			return g.emit("replace", ptr, b)
+		}
+	case MappingNode:
+		return g.diffMapping(ptr, a, b)
+	case SequenceNode:
+		return g.diffSequence(ptr, a, b)
+	}
+	return nil
+}
+
+func (g *patchGenerator) diffMapping(ptr string, a, b *Node) error {
+	ea, eb := mappingEntries(a), mappingEntries(b)
+	keys := func(entries []*Node) (map[string]*Node, error) {
+		m := make(map[string]*Node, len(entries)/2)
+		for i := 0; i+1 < len(entries); i += 2 {
+			k := derefAlias(entries[i])
+			if k.Kind != ScalarNode || resolvedTag(k) != strTag {
+				return nil, fmt.Errorf("yaml: cannot create JSON patch: mapping key at line %d is not a string", k.Line)
+			}
+			m[k.Value] = entries[i+1]
+		}
+		return m, nil
+	}
+	ka, err := keys(ea)
+	if err != nil {
+		return err
+	}
+	kb, err := keys(eb)
+	if err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(ea); i += 2 {
+		key := derefAlias(ea[i]).Value
+		if _, ok := kb[key]; !ok {
+			if err := g.emit("remove", appendPointer(ptr, key), nil); err != nil {
+				return err
+			}
+		}
+	}
+	for i := 0; i+1 < len(ea); i += 2 {
+		key := derefAlias(ea[i]).Value
+		if vb, ok := kb[key]; ok {
+			if err := g.diff(appendPointer(ptr, key), ea[i+1], vb); err != nil {
+				return err
+			}
+		}
+	}
+	for i := 0; i+1 < len(eb); i += 2 {
+		key := derefAlias(eb[i]).Value
+		if _, ok := ka[key]; !ok {
+			if err := g.emit("add", appendPointer(ptr, key), eb[i+1]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// diffSequence emits operations for two aligned sequences. Indexes in the
+// operations account for the effect of the operations before them.
+func (g *patchGenerator) diffSequence(ptr string, a, b *Node) error {
+	pos := 0 // Index in the sequence as patched so far
+	for _, hunk := range alignSequences(a, b) {
+		pos += hunk.equal
+		paired := hunk.paired()
+		for k := 0; k < paired; k++ {
+			i, j := hunk.removed[k], hunk.added[k]
+			if err := g.diff(appendPointer(ptr, strconv.Itoa(pos)), a.Content[i], b.Content[j]); err != nil {
+				return err
+			}
+			pos++
+		}
+		for range hunk.removed[paired:] {
+			if err := g.emit("remove", appendPointer(ptr, strconv.Itoa(pos)), nil); err != nil {
+				return err
+			}
+		}
+		for _, j := range hunk.added[paired:] {
+			if err := g.emit("add", appendPointer(ptr, strconv.Itoa(pos)), b.Content[j]); err != nil {
+				return err
+			}
+			pos++
+		}
+	}
+	return nil
+}
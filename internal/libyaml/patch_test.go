@@ -0,0 +1,182 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for JSON Patch and JSON Merge Patch over Node trees.
+
+package libyaml
+
+import (
+	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+// patchDoc loads src, applies patch with apply and dumps the result.
+func patchDoc(t *testing.T, src, patch string, apply func(*Node, []byte) error) (string, error) {
+	t.Helper()
+	var doc Node
+	assert.NoError(t, Load([]byte(src), &doc))
+	if err := apply(&doc, []byte(patch)); err != nil {
+		return "", err
+	}
+	out, err := Dump(&doc)
+	assert.NoError(t, err)
+	return string(out), nil
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	const src = `# service config
+name: web # service name
+ports:
+- 80
+- 443
+env:
+  MODE: dev
+`
+	tests := []struct {
+		name, patch, want string
+	}{{
+		name:  "replace keeps comment",
+		patch: `[{"op": "replace", "path": "/name", "value": "api"}]`,
+		want:  "# service config\nname: api # service name\nports:\n- 80\n- 443\nenv:\n  MODE: dev\n",
+	}, {
+		name:  "add to sequence and mapping",
+		patch: `[{"op": "add", "path": "/ports/1", "value": 8080}, {"op": "add", "path": "/ports/-", "value": 9090}, {"op": "add", "path": "/env/DEBUG", "value": "1"}]`,
+		want:  "# service config\nname: web # service name\nports:\n- 80\n- 8080\n- 443\n- 9090\nenv:\n  MODE: dev\n  DEBUG: '1'\n",
+	}, {
+		name:  "add inserts block style",
+		patch: `[{"op": "add", "path": "/limits", "value": {"cpu": 2, "names": ["a"]}}]`,
+		want:  "# service config\nname: web # service name\nports:\n- 80\n- 443\nenv:\n  MODE: dev\nlimits:\n  cpu: 2\n  names:\n  - a\n",
+	}, {
+		name:  "remove",
+		patch: `[{"op": "remove", "path": "/ports/0"}, {"op": "remove", "path": "/env"}]`,
+		want:  "# service config\nname: web # service name\nports:\n- 443\n",
+	}, {
+		name:  "move and copy",
+		patch: `[{"op": "copy", "from": "/name", "path": "/env/NAME"}, {"op": "move", "from": "/ports", "path": "/env/PORTS"}]`,
+		want:  "# service config\nname: web # service name\nenv:\n  MODE: dev\n  NAME: web\n  PORTS:\n  - 80\n  - 443\n",
+	}, {
+		name:  "test compares numbers numerically",
+		patch: `[{"op": "test", "path": "/ports/0", "value": 80.0}, {"op": "test", "path": "/env", "value": {"MODE": "dev"}}]`,
+		want:  src,
+	}, {
+		name:  "patch written in YAML",
+		patch: "- {op: replace, path: /env/MODE, value: prod}\n",
+		want:  "# service config\nname: web # service name\nports:\n- 80\n- 443\nenv:\n  MODE: prod\n",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := patchDoc(t, src, tc.patch, ApplyJSONPatch)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestApplyJSONPatchPointers(t *testing.T) {
+	const src = "a/b: 1\nm~n: 2\nd: &d {x: 1}\nuse: *d\nm:\n  <<: *d\n"
+	got, err := patchDoc(t, src, `[
+		{"op": "replace", "path": "/a~1b", "value": 10},
+		{"op": "replace", "path": "/m~0n", "value": 20},
+		{"op": "replace", "path": "/use/x", "value": 2},
+		{"op": "replace", "path": "/m/x", "value": 3}
+	]`, ApplyJSONPatch)
+	assert.NoError(t, err)
+	// Edits through aliases and merge keys do not change the anchor.
+	assert.Equal(t, "a/b: 10\nm~n: 20\nd: &d {x: 1}\nuse: {x: 2}\nm:\n  <<: *d\n  x: 3\n", got)
+}
+
+func TestApplyJSONPatchErrors(t *testing.T) {
+	const src = "a: 1\nl: [1, 2]\n"
+	tests := []struct {
+		patch, want string
+	}{
+		{`{"op": "add"}`, `yaml: invalid json patch: expected a sequence of operations`},
+		{`[{"op": "add", "path": "/b"}]`, `yaml: json patch operation 0: add operation requires a value`},
+		{`[{"path": "/b"}]`, `yaml: json patch operation 0: missing string member "op"`},
+		{`[{"op": "frob", "path": "/a"}]`, `yaml: json patch operation 0: unknown operation "frob"`},
+		{`[{"op": "remove", "path": "a"}]`, `.*invalid JSON pointer "a": must start with '/'`},
+		{`[{"op": "remove", "path": "/l/01"}]`, `.*invalid array index "01"`},
+		{`[{"op": "remove", "path": "/l/5"}]`, `yaml: json patch operation 0: yaml: not found: /l/5`},
+		{`[{"op": "replace", "path": "/b", "value": 1}]`, `.*not found: /b`},
+		{`[{"op": "add", "path": "/l/3", "value": 1}]`, `.*insert position 3 out of range for 2 entries`},
+		{`[{"op": "move", "from": "/l", "path": "/l/0"}]`, `.*cannot move a value into itself`},
+		{`[{"op": "add", "path": "/b", "value": 1}, {"op": "test", "path": "/a", "value": 2}]`, `yaml: json patch operation 1: test failed: value at "/a" differs`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.patch, func(t *testing.T) {
+			var doc Node
+			assert.NoError(t, Load([]byte(src), &doc))
+			err := ApplyJSONPatch(&doc, []byte(tc.patch))
+			assert.ErrorMatches(t, tc.want, err)
+			// Failed patches leave the document untouched.
+			out, err := Dump(&doc)
+			assert.NoError(t, err)
+			assert.Equal(t, src, string(out))
+		})
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	const src = "# config\ntitle: Goodbye! # greeting\nauthor:\n  givenName: John\n  familyName: Doe\ntags: [example, sample]\ncontent: This will be unchanged\n"
+	got, err := patchDoc(t, src, `{
+		"title": "Hello!",
+		"phoneNumber": "+01-123-456-7890",
+		"author": {"familyName": null},
+		"tags": ["example"]
+	}`, ApplyMergePatch)
+	assert.NoError(t, err)
+	assert.Equal(t, "# config\ntitle: Hello! # greeting\nauthor:\n  givenName: John\ntags: [example]\ncontent: This will be unchanged\nphoneNumber: +01-123-456-7890\n", got)
+
+	got, err = patchDoc(t, "a: 1\n", `[1, 2]`, ApplyMergePatch)
+	assert.NoError(t, err)
+	assert.Equal(t, "- 1\n- 2\n", got)
+}
+
+func TestCreatePatch(t *testing.T) {
+	tests := []struct {
+		name, a, b, want string
+	}{{
+		name: "equal",
+		a:    "a: 0x10\n",
+		b:    "a: 16\n",
+		want: `[]`,
+	}, {
+		name: "mapping",
+		a:    "keep: 1\nold: 2\nchange: {x: 1}\n",
+		b:    "keep: 1\nchange: {x: 2}\nnew/key: [1]\n",
+		want: `[{"op":"remove","path":"/old"},{"op":"replace","path":"/change/x","value":2},{"op":"add","path":"/new~1key","value":[1]}]`,
+	}, {
+		name: "sequence",
+		a:    "[a, b, c, d]\n",
+		b:    "[x, a, c, d, e]\n",
+		want: `[{"op":"add","path":"/0","value":"x"},{"op":"remove","path":"/2"},{"op":"add","path":"/4","value":"e"}]`,
+	}, {
+		name: "type change",
+		a:    "a: '1'\n",
+		b:    "a: 1\n",
+		want: `[{"op":"replace","path":"/a","value":1}]`,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var a, b Node
+			assert.NoError(t, Load([]byte(tc.a), &a))
+			assert.NoError(t, Load([]byte(tc.b), &b))
+			patch, err := CreatePatch(&a, &b)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, string(patch))
+
+			// Applying the patch turns a into b.
+			assert.NoError(t, ApplyJSONPatch(&a, patch))
+			changes, err := Diff(&a, &b)
+			assert.NoError(t, err)
+			assert.Equal(t, 0, len(changes))
+		})
+	}
+
+	var a, b Node
+	assert.NoError(t, Load([]byte("1: a\n"), &a))
+	assert.NoError(t, Load([]byte("1: b\n"), &b))
+	_, err := CreatePatch(&a, &b)
+	assert.ErrorMatches(t, `yaml: cannot create JSON patch: mapping key at line 1 is not a string`, err)
+}
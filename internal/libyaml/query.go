@@ -0,0 +1,1035 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Path queries over Node trees.
+//
+// A path expression is compiled once into a Path and then evaluated against
+// any number of node trees. The syntax is a JSONPath dialect adapted to YAML:
+//
+//	$                     the root node (optional at the start)
+//	.key  ['key']         child of a mapping by key
+//	.*  [*]               every mapping value or sequence item
+//	..key  ..*  ..[...]   recursive descent, then apply the selector
+//	[0]  [-1]  [0, 2]     sequence items by index (negative counts from end)
+//	[1:3]  [::2]          sequence slice (start:end:step)
+//	[?(@.kind == 'Pod')]  items for which a filter expression holds
+//
+// Filter expressions support @ (the candidate node) and $ (the root) followed
+// by relative segments, comparisons (== != < <= > >= =~), the logical
+// operators && || !, parentheses, and string, number, true, false and null
+// literals. Scalars are compared by their resolved value, so `@.port == 80`
+// matches both `80` and `0x50`.
+//
+// Evaluation follows alias nodes to their anchors and expands << merge keys
+// with the same precedence the Constructor uses: keys defined directly in a
+// mapping override merged ones, and earlier merge sources override later
+// ones.
+
+package libyaml
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Path is a compiled path expression that can be evaluated against node
+// trees.
+// A Path is safe for concurrent use by multiple goroutines.
+type Path struct {
+	expr     string
+	segments []pathSegment
+}
+
+// pathSegment is a single step of a path expression.
+type pathSegment struct {
+	recursive bool // Apply the selector to the node and all descendants
+	sel       pathSelector
+}
+
+// pathSelector selects children of a node.
+type pathSelector interface {
+	selectFrom(ctx *pathContext, n *Node, out []*Node) []*Node
+}
+
+// pathContext carries evaluation state for a single Find call.
+type pathContext struct {
+	root *Node
+}
+
+// CompilePath parses a path expression and returns a Path that can be
+// evaluated against node trees.
+func CompilePath(expr string) (*Path, error) {
+	p := &pathParser{s: expr}
+	segments, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+	return &Path{expr: expr, segments: segments}, nil
+}
+
+// MustCompilePath is like CompilePath but panics if the expression cannot be
+// parsed. It simplifies safe initialization of global variables holding
+// compiled paths.
+func MustCompilePath(expr string) *Path {
+	p, err := CompilePath(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the source text used to compile the path.
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Find evaluates the path against n and returns the matching nodes in
+// document order. A DocumentNode is transparently replaced by its content.
+// Each node is reported at most once, even when it is reachable through
+// several aliases. It returns an error if n has an alias cycle.
+func (p *Path) Find(n *Node) (nodes []*Node, err error) {
+	defer handleErr(&err)
+	root := queryRoot(n)
+	if root == nil {
+		return nil, nil
+	}
+	ctx := &pathContext{root: root}
+	return ctx.eval(p.segments, root), nil
+}
+
+// Query compiles expr and evaluates it against the node.
+// See [CompilePath] for the expression syntax.
+func (n *Node) Query(expr string) ([]*Node, error) {
+	p, err := CompilePath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Find(n)
+}
+
+// queryRoot returns the node a query starts from: documents are replaced by
+// their content and aliases are followed.
+func queryRoot(n *Node) *Node {
+	n = derefAlias(n)
+	if n != nil && n.Kind == DocumentNode {
+		if len(n.Content) == 0 {
+			return nil
+		}
+		n = derefAlias(n.Content[0])
+	}
+	return n
+}
+
+// eval applies segments in order starting from the start node.
+func (ctx *pathContext) eval(segments []pathSegment, start *Node) []*Node {
+	current := []*Node{start}
+	for _, seg := range segments {
+		var next []*Node
+		seen := make(map[*Node]bool)
+		for _, n := range current {
+			var candidates []*Node
+			if seg.recursive {
+				candidates = descendants(n)
+			} else {
+				candidates = []*Node{n}
+			}
+			for _, c := range candidates {
+				for _, m := range seg.sel.selectFrom(ctx, c, nil) {
+					m = derefAlias(m)
+					if m == nil || seen[m] {
+						continue
+					}
+					seen[m] = true
+					next = append(next, m)
+				}
+			}
+		}
+		current = next
+		if len(current) == 0 {
+			break
+		}
+	}
+	return current
+}
+
+// errAliasChain is the error for alias chains that do not reach a node.
+var errAliasChain = errors.New("yaml: alias chain does not reach a node (cycle or more than 1000 aliases)")
+
+// derefAlias follows alias nodes to the node they reference. It fails with
+// errAliasChain if the aliases form a cycle, which only hand-built trees
+// can do.
+func derefAlias(n *Node) *Node {
+	for i := 0; n != nil && n.Kind == AliasNode; i++ {
+		if i > 1000 {
+			Fail(errAliasChain)
+		}
+		n = n.Alias
+	}
+	return n
+}
+
+// isMergeKey reports whether n is a << merge key, either resolved (tagged
+// !!merge) or still unresolved in a tree that has not been through the
+// Resolver.
+func isMergeKey(n *Node) bool {
+	n = derefAlias(n)
+	if n == nil || n.Kind != ScalarNode {
+		return false
+	}
+	if isMerge(n) {
+		return true
+	}
+	return n.Tag == "" && n.Style == 0 && n.Value == "<<"
+}
+
+// mappingEntries returns the effective key/value pairs of a mapping node as a
+// flat key, value, key, value slice. Merge keys are expanded in place of the
+// << entry: keys defined directly in the mapping win over merged ones, and
+// earlier merge sources win over later ones, matching Constructor.merge.
+func mappingEntries(n *Node) []*Node {
+	var entries []*Node
+	seen := make(map[string]bool)
+	visiting := make(map[*Node]bool)
+	var collect func(m *Node)
+	collect = func(m *Node) {
+		m = derefAlias(m)
+		if m == nil || m.Kind != MappingNode || visiting[m] {
+			return
+		}
+		visiting[m] = true
+		defer delete(visiting, m)
+		var merges []*Node
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			k := m.Content[i]
+			if isMergeKey(k) {
+				merges = append(merges, m.Content[i+1])
+				continue
+			}
+			key := entryKey(k)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			entries = append(entries, k, m.Content[i+1])
+		}
+		for _, merge := range merges {
+			merge = derefAlias(merge)
+			if merge == nil {
+				continue
+			}
+			if merge.Kind == SequenceNode {
+				for _, item := range merge.Content {
+					collect(item)
+				}
+			} else {
+				collect(merge)
+			}
+		}
+	}
+	collect(n)
+	return entries
+}
+
+// entryKey returns a comparable identity for a mapping key node.
+// Scalar keys are identified by their value; other keys by their address.
+func entryKey(k *Node) string {
+	k = derefAlias(k)
+	if k == nil {
+		return ""
+	}
+	if k.Kind == ScalarNode {
+		return "s:" + k.Value
+	}
+	return fmt.Sprintf("p:%p", k)
+}
+
+// children returns the effective child values of a collection node:
+// mapping values (with merge keys expanded) or sequence items.
+func children(n *Node) []*Node {
+	n = derefAlias(n)
+	if n == nil {
+		return nil
+	}
+	switch n.Kind {
+	case MappingNode:
+		entries := mappingEntries(n)
+		values := make([]*Node, 0, len(entries)/2)
+		for i := 1; i < len(entries); i += 2 {
+			values = append(values, entries[i])
+		}
+		return values
+	case SequenceNode:
+		return n.Content
+	case DocumentNode:
+		return n.Content
+	}
+	return nil
+}
+
+// descendants returns n followed by all nodes below it in pre-order,
+// following aliases and visiting each node once.
+func descendants(n *Node) []*Node {
+	var out []*Node
+	seen := make(map[*Node]bool)
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		n = derefAlias(n)
+		if n == nil || seen[n] {
+			return
+		}
+		seen[n] = true
+		out = append(out, n)
+		for _, c := range children(n) {
+			walk(c)
+		}
+	}
+	walk(n)
+	return out
+}
+
+// --------------------------------------------------------------------------
+// Selectors
+
+// keySelector selects mapping values by key.
+type keySelector struct {
+	keys []string
+}
+
+func (s keySelector) selectFrom(ctx *pathContext, n *Node, out []*Node) []*Node {
+	n = derefAlias(n)
+	if n == nil || n.Kind != MappingNode {
+		return out
+	}
+	entries := mappingEntries(n)
+	for _, key := range s.keys {
+		for i := 0; i+1 < len(entries); i += 2 {
+			k := derefAlias(entries[i])
+			if k != nil && k.Kind == ScalarNode && k.Value == key {
+				out = append(out, entries[i+1])
+				break
+			}
+		}
+	}
+	return out
+}
+
+// wildcardSelector selects every mapping value or sequence item.
+type wildcardSelector struct{}
+
+func (wildcardSelector) selectFrom(ctx *pathContext, n *Node, out []*Node) []*Node {
+	return append(out, children(n)...)
+}
+
+// indexSelector selects sequence items by index.
+type indexSelector struct {
+	indexes []int
+}
+
+func (s indexSelector) selectFrom(ctx *pathContext, n *Node, out []*Node) []*Node {
+	n = derefAlias(n)
+	if n == nil || n.Kind != SequenceNode {
+		return out
+	}
+	l := len(n.Content)
+	for _, i := range s.indexes {
+		if i < 0 {
+			i += l
+		}
+		if i >= 0 && i < l {
+			out = append(out, n.Content[i])
+		}
+	}
+	return out
+}
+
+// sliceSelector selects a range of sequence items.
+type sliceSelector struct {
+	start, end *int
+	step       int
+}
+
+func (s sliceSelector) selectFrom(ctx *pathContext, n *Node, out []*Node) []*Node {
+	n = derefAlias(n)
+	if n == nil || n.Kind != SequenceNode || s.step == 0 {
+		return out
+	}
+	l := len(n.Content)
+	norm := func(i int) int {
+		if i < 0 {
+			i += l
+		}
+		if i < 0 {
+			return 0
+		}
+		if i > l {
+			return l
+		}
+		return i
+	}
+	if s.step > 0 {
+		start, end := 0, l
+		if s.start != nil {
+			start = norm(*s.start)
+		}
+		if s.end != nil {
+			end = norm(*s.end)
+		}
+		for i := start; i < end; i += s.step {
+			out = append(out, n.Content[i])
+		}
+		return out
+	}
+	start, end := l-1, -1
+	if s.start != nil {
+		start = norm(*s.start)
+		if start >= l {
+			start = l - 1
+		}
+	}
+	if s.end != nil {
+		end = norm(*s.end)
+	}
+	for i := start; i > end && i >= 0; i += s.step {
+		out = append(out, n.Content[i])
+	}
+	return out
+}
+
+// filterSelector selects children for which a filter expression holds.
+type filterSelector struct {
+	expr filterExpr
+}
+
+func (s filterSelector) selectFrom(ctx *pathContext, n *Node, out []*Node) []*Node {
+	for _, c := range children(n) {
+		if s.expr.test(ctx, derefAlias(c)) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// --------------------------------------------------------------------------
+// Filter expressions
+
+// filterExpr is a boolean expression evaluated against a candidate node.
+type filterExpr interface {
+	test(ctx *pathContext, current *Node) bool
+}
+
+// filterOperand produces a value for comparison: either a literal or the
+// first node matched by a relative or absolute path.
+type filterOperand struct {
+	literal  any
+	isLit    bool
+	absolute bool
+	segments []pathSegment
+}
+
+// value returns the operand value and whether it exists.
+func (o *filterOperand) value(ctx *pathContext, current *Node) (any, bool) {
+	if o.isLit {
+		return o.literal, true
+	}
+	nodes := o.nodes(ctx, current)
+	if len(nodes) == 0 {
+		return nil, false
+	}
+	return scalarValue(nodes[0])
+}
+
+// nodes returns the nodes matched by a path operand.
+func (o *filterOperand) nodes(ctx *pathContext, current *Node) []*Node {
+	start := current
+	if o.absolute {
+		start = ctx.root
+	}
+	if start == nil {
+		return nil
+	}
+	return ctx.eval(o.segments, start)
+}
+
+// existsExpr holds when its path operand matches at least one node.
+type existsExpr struct {
+	operand *filterOperand
+}
+
+func (e existsExpr) test(ctx *pathContext, current *Node) bool {
+	return len(e.operand.nodes(ctx, current)) > 0
+}
+
+// notExpr negates its operand.
+type notExpr struct {
+	expr filterExpr
+}
+
+func (e notExpr) test(ctx *pathContext, current *Node) bool {
+	return !e.expr.test(ctx, current)
+}
+
+// logicalExpr combines two expressions with && or ||.
+type logicalExpr struct {
+	and         bool
+	left, right filterExpr
+}
+
+func (e logicalExpr) test(ctx *pathContext, current *Node) bool {
+	if e.and {
+		return e.left.test(ctx, current) && e.right.test(ctx, current)
+	}
+	return e.left.test(ctx, current) || e.right.test(ctx, current)
+}
+
+// compareExpr compares two operands.
+type compareExpr struct {
+	op          string
+	left, right *filterOperand
+	re          *regexp.Regexp
+}
+
+func (e compareExpr) test(ctx *pathContext, current *Node) bool {
+	lv, lok := e.left.value(ctx, current)
+	if e.op == "=~" {
+		s, ok := lv.(string)
+		return lok && ok && e.re.MatchString(s)
+	}
+	rv, rok := e.right.value(ctx, current)
+	switch e.op {
+	case "==":
+		return lok == rok && (!lok || valuesEqual(lv, rv))
+	case "!=":
+		return lok != rok || (lok && !valuesEqual(lv, rv))
+	}
+	if !lok || !rok {
+		return false
+	}
+	c, ok := compareValues(lv, rv)
+	if !ok {
+		return false
+	}
+	switch e.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// scalarValue returns the resolved Go value of a scalar node.
+// Collections are reported as existing but have no comparable value.
+func scalarValue(n *Node) (any, bool) {
+	n = derefAlias(n)
+	if n == nil {
+		return nil, false
+	}
+	if n.Kind != ScalarNode {
+		return n, true
+	}
+	_, v := resolveScalar(n)
+	return v, true
+}
+
+// numericValue converts resolved numeric values to float64.
+func numericValue(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// valuesEqual reports whether two resolved values are equal.
+func valuesEqual(a, b any) bool {
+	if af, ok := numericValue(a); ok {
+		bf, ok := numericValue(b)
+		return ok && (af == bf || math.IsNaN(af) && math.IsNaN(bf))
+	}
+	switch a := a.(type) {
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	case nil:
+		return b == nil
+	case *Node:
+		b, ok := b.(*Node)
+		return ok && a == b
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// compareValues orders two resolved values of the same kind.
+func compareValues(a, b any) (int, bool) {
+	if af, ok := numericValue(a); ok {
+		bf, ok := numericValue(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case af < bf:
+			return -1, true
+		case af > bf:
+			return 1, true
+		}
+		return 0, true
+	}
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if aok && bok {
+		return strings.Compare(as, bs), true
+	}
+	return 0, false
+}
+
+// --------------------------------------------------------------------------
+// Parser
+
+// pathParser is a recursive descent parser for path expressions.
+type pathParser struct {
+	s   string
+	pos int
+}
+
+// errorf returns a compile error pointing at the current position.
+func (p *pathParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("yaml: invalid path %q at offset %d: %s", p.s, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *pathParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *pathParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *pathParser) skipSpace() {
+	for !p.eof() && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// consume advances past tok if it is next in the input.
+func (p *pathParser) consume(tok string) bool {
+	if strings.HasPrefix(p.s[p.pos:], tok) {
+		p.pos += len(tok)
+		return true
+	}
+	return false
+}
+
+// parsePath parses a complete top-level expression.
+func (p *pathParser) parsePath() ([]pathSegment, error) {
+	p.skipSpace()
+	var segments []pathSegment
+	switch c := p.peek(); {
+	case c == '$':
+		p.pos++
+	case isPathNameChar(c):
+		// A leading bare key, as in "spec.containers".
+		seg, err := p.parseNameSegment(false)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+	}
+	rest, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	segments = append(segments, rest...)
+	p.skipSpace()
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q", p.peek())
+	}
+	return segments, nil
+}
+
+// parseSegments parses segments until a character that cannot start one.
+func (p *pathParser) parseSegments() ([]pathSegment, error) {
+	var segments []pathSegment
+	for !p.eof() {
+		var seg pathSegment
+		var err error
+		switch p.peek() {
+		case '.':
+			p.pos++
+			recursive := p.consume(".")
+			switch c := p.peek(); {
+			case recursive && c == '[':
+				seg, err = p.parseBracket(true)
+			case c == '*':
+				p.pos++
+				seg = pathSegment{recursive: recursive, sel: wildcardSelector{}}
+			case isPathNameChar(c):
+				seg, err = p.parseNameSegment(recursive)
+			default:
+				return nil, p.errorf("expected key or '*' after '.'")
+			}
+		case '[':
+			seg, err = p.parseBracket(false)
+		default:
+			return segments, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+// isPathNameChar reports whether c may appear in an unquoted key.
+func isPathNameChar(c byte) bool {
+	if c == 0 {
+		return false
+	}
+	return !strings.ContainsRune(" \t\n.[]()=!<>&|,'\"~*$@", rune(c))
+}
+
+// parseNameSegment parses an unquoted key.
+func (p *pathParser) parseNameSegment(recursive bool) (pathSegment, error) {
+	start := p.pos
+	for isPathNameChar(p.peek()) {
+		p.pos++
+	}
+	if p.pos == start {
+		return pathSegment{}, p.errorf("expected key")
+	}
+	return pathSegment{recursive: recursive, sel: keySelector{keys: []string{p.s[start:p.pos]}}}, nil
+}
+
+// parseBracket parses a [...] selector.
+func (p *pathParser) parseBracket(recursive bool) (pathSegment, error) {
+	p.pos++ // '['
+	p.skipSpace()
+	var sel pathSelector
+	var err error
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		sel = wildcardSelector{}
+	case c == '?':
+		p.pos++
+		sel, err = p.parseFilter()
+	case c == '\'' || c == '"':
+		sel, err = p.parseKeyList()
+	case c == '-' || c == ':' || c >= '0' && c <= '9':
+		sel, err = p.parseIndexOrSlice()
+	default:
+		return pathSegment{}, p.errorf("unexpected %q in brackets", c)
+	}
+	if err != nil {
+		return pathSegment{}, err
+	}
+	p.skipSpace()
+	if !p.consume("]") {
+		return pathSegment{}, p.errorf("expected ']'")
+	}
+	return pathSegment{recursive: recursive, sel: sel}, nil
+}
+
+// parseKeyList parses one or more comma separated quoted keys.
+func (p *pathParser) parseKeyList() (pathSelector, error) {
+	var keys []string
+	for {
+		p.skipSpace()
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		p.skipSpace()
+		if !p.consume(",") {
+			return keySelector{keys: keys}, nil
+		}
+	}
+}
+
+// parseString parses a single or double quoted string with backslash
+// escapes.
+func (p *pathParser) parseString() (string, error) {
+	quote := p.peek()
+	if quote != '\'' && quote != '"' {
+		return "", p.errorf("expected quoted string")
+	}
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case quote:
+			return b.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", p.errorf("unterminated string")
+			}
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+// parseInt parses an optionally signed decimal integer.
+func (p *pathParser) parseInt() (int, bool, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for c := p.peek(); c >= '0' && c <= '9'; c = p.peek() {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, false, nil
+	}
+	n, err := strconv.Atoi(p.s[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, false, p.errorf("invalid index")
+	}
+	return n, true, nil
+}
+
+// parseIndexOrSlice parses an index list ([0, 2]) or a slice ([1:3:1]).
+func (p *pathParser) parseIndexOrSlice() (pathSelector, error) {
+	first, ok, err := p.parseInt()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.peek() == ':' {
+		s := sliceSelector{step: 1}
+		if ok {
+			s.start = &first
+		}
+		p.pos++
+		p.skipSpace()
+		end, ok, err := p.parseInt()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			s.end = &end
+		}
+		p.skipSpace()
+		if p.consume(":") {
+			p.skipSpace()
+			step, ok, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				if step == 0 {
+					return nil, p.errorf("slice step cannot be zero")
+				}
+				s.step = step
+			}
+		}
+		return s, nil
+	}
+	if !ok {
+		return nil, p.errorf("expected index")
+	}
+	indexes := []int{first}
+	for {
+		p.skipSpace()
+		if !p.consume(",") {
+			return indexSelector{indexes: indexes}, nil
+		}
+		p.skipSpace()
+		n, ok, err := p.parseInt()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, p.errorf("expected index")
+		}
+		indexes = append(indexes, n)
+	}
+}
+
+// parseFilter parses the body of a [?...] selector. The expression may be
+// wrapped in parentheses, as in [?(@.a)], or bare, as in [?@.a].
+func (p *pathParser) parseFilter() (pathSelector, error) {
+	p.skipSpace()
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	return filterSelector{expr: expr}, nil
+}
+
+func (p *pathParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{left: left, right: right}
+	}
+}
+
+func (p *pathParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{and: true, left: left, right: right}
+	}
+}
+
+func (p *pathParser) parseUnary() (filterExpr, error) {
+	p.skipSpace()
+	if p.peek() == '!' && !strings.HasPrefix(p.s[p.pos:], "!=") {
+		p.pos++
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr: expr}, nil
+	}
+	if p.consume("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume(")") {
+			return nil, p.errorf("expected ')'")
+		}
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+// comparisonOps lists comparison operators, longest first.
+var comparisonOps = []string{"==", "!=", "<=", ">=", "=~", "<", ">"}
+
+func (p *pathParser) parseComparison() (filterExpr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	op := ""
+	for _, candidate := range comparisonOps {
+		if p.consume(candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		if left.isLit {
+			return nil, p.errorf("literal cannot be used as a condition")
+		}
+		return existsExpr{operand: left}, nil
+	}
+	p.skipSpace()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	expr := compareExpr{op: op, left: left, right: right}
+	if op == "=~" {
+		s, ok := right.literal.(string)
+		if !right.isLit || !ok {
+			return nil, p.errorf("=~ requires a string pattern")
+		}
+		expr.re, err = regexp.Compile(s)
+		if err != nil {
+			return nil, p.errorf("invalid pattern: %v", err)
+		}
+	}
+	return expr, nil
+}
+
+func (p *pathParser) parseOperand() (*filterOperand, error) {
+	p.skipSpace()
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return &filterOperand{absolute: c == '$', segments: segments}, nil
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &filterOperand{literal: s, isLit: true}, nil
+	case c == '-' || c == '+' || c == '.' || c >= '0' && c <= '9':
+		start := p.pos
+		for !p.eof() && strings.IndexByte("+-.0123456789eE_xXoOabcdefABCDEF", p.s[p.pos]) >= 0 {
+			p.pos++
+		}
+		tag, v := resolve("", p.s[start:p.pos])
+		if tag != intTag && tag != floatTag {
+			p.pos = start
+			return nil, p.errorf("invalid number")
+		}
+		return &filterOperand{literal: v, isLit: true}, nil
+	}
+	for _, lit := range []struct {
+		text  string
+		value any
+	}{{"true", true}, {"false", false}, {"null", nil}} {
+		end := p.pos + len(lit.text)
+		if strings.HasPrefix(p.s[p.pos:], lit.text) && (end == len(p.s) || !isWordChar(p.s[end])) {
+			p.pos = end
+			return &filterOperand{literal: lit.value, isLit: true}, nil
+		}
+	}
+	return nil, p.errorf("expected operand")
+}
+
+// isWordChar reports whether c continues a word, so that a literal such as
+// null is not matched as the start of nullable.
+func isWordChar(c byte) bool {
+	return c == '_' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
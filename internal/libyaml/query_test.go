@@ -0,0 +1,186 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for path queries over Node trees.
+
+package libyaml
+
+import (
+	"fmt"
+	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+const queryTestDoc = `
+kind: Pod
+spec:
+  containers:
+  - name: web
+    image: nginx:1.25
+    port: 80
+  - name: sidecar
+    image: envoy:1.29
+    port: 0x2382
+  - name: debug
+    image: busybox
+    enabled: false
+defaults: &defaults
+  timeout: 30
+  retries: 3
+override: &override
+  retries: 5
+service:
+  <<: [*override, *defaults]
+  timeout: 10
+  name: api
+first: *defaults
+`
+
+// queryValues loads doc, runs expr and renders each match as
+// "value@line:column" for scalars or "kind@line:column" for collections.
+func queryValues(t *testing.T, doc, expr string) []string {
+	t.Helper()
+	var root Node
+	assert.NoError(t, Load([]byte(doc), &root))
+	nodes, err := root.Query(expr)
+	assert.NoError(t, err)
+	out := []string{}
+	for _, n := range nodes {
+		label := n.Value
+		if n.Kind != ScalarNode {
+			label = fmt.Sprint(n.Kind)
+		}
+		out = append(out, fmt.Sprintf("%s@%d:%d", label, n.Line, n.Column))
+	}
+	return out
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"kind", []string{"Pod@2:7"}},
+		{"$.kind", []string{"Pod@2:7"}},
+		{"$['kind']", []string{"Pod@2:7"}},
+		{"spec.containers[*].image", []string{"nginx:1.25@6:12", "envoy:1.29@9:12", "busybox@12:12"}},
+		{"spec.containers.*.name", []string{"web@5:11", "sidecar@8:11", "debug@11:11"}},
+		{"spec.containers[0].name", []string{"web@5:11"}},
+		{"spec.containers[-1].name", []string{"debug@11:11"}},
+		{"spec.containers[0, 2].name", []string{"web@5:11", "debug@11:11"}},
+		{"spec.containers[1:].name", []string{"sidecar@8:11", "debug@11:11"}},
+		{"spec.containers[::2].name", []string{"web@5:11", "debug@11:11"}},
+		{"spec.containers[::-1].name", []string{"debug@11:11", "sidecar@8:11", "web@5:11"}},
+		{"spec.containers[5].name", []string{}},
+		{"$..image", []string{"nginx:1.25@6:12", "envoy:1.29@9:12", "busybox@12:12"}},
+		{"$..[1].name", []string{"sidecar@8:11"}},
+		{"spec['containers', 'missing'][0]['name']", []string{"web@5:11"}},
+
+		// Filters
+		{"spec.containers[?(@.port == 80)].name", []string{"web@5:11"}},
+		{"spec.containers[?(@.port == 9090)].name", []string{"sidecar@8:11"}},
+		{"spec.containers[?(@.port > 100)].name", []string{"sidecar@8:11"}},
+		{"spec.containers[?(@.port)].name", []string{"web@5:11", "sidecar@8:11"}},
+		{"spec.containers[?(!@.port)].name", []string{"debug@11:11"}},
+		{"spec.containers[?(@.enabled == false)].name", []string{"debug@11:11"}},
+		{"spec.containers[?(@.image =~ '^envoy:')].name", []string{"sidecar@8:11"}},
+		{"spec.containers[?(@.name == 'web' || @.name == \"debug\")].port", []string{"80@7:11"}},
+		{"spec.containers[?(@.port && (@.port < 100 || @.name == 'sidecar'))].name", []string{"web@5:11", "sidecar@8:11"}},
+		{"spec.containers[?(@.name != 'web')].name", []string{"sidecar@8:11", "debug@11:11"}},
+		{"spec.containers[?(@.port == null)].name", []string{}}, // missing is not null
+		{"spec.containers[?($.kind == 'Pod' && @.port >= 9090)].name", []string{"sidecar@8:11"}},
+
+		// Merge keys: direct keys win, then earlier merge sources win.
+		{"service.timeout", []string{"10@21:12"}},
+		{"service.retries", []string{"5@18:12"}},
+		{"service.name", []string{"api@22:9"}},
+		{"service.*", []string{"10@21:12", "api@22:9", "5@18:12"}},
+
+		// Aliases are followed to their anchors.
+		{"first.retries", []string{"3@16:12"}},
+		{"$..retries", []string{"3@16:12", "5@18:12"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			assert.DeepEqual(t, tc.want, queryValues(t, queryTestDoc, tc.expr))
+		})
+	}
+}
+
+func TestQueryMergeCycle(t *testing.T) {
+	var n Node
+	assert.NoError(t, Load([]byte("a: &a\n  x: 1\n"), &n))
+	a := n.Content[0].Content[1]
+	// Build a self-referencing merge that the parser would never produce.
+	a.Content = append(a.Content,
+		&Node{Kind: ScalarNode, Tag: mergeTag, Value: "<<"},
+		&Node{Kind: AliasNode, Alias: a, Value: "a"})
+	nodes, err := n.Query("a.*")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(nodes))
+	assert.Equal(t, "1", nodes[0].Value)
+}
+
+func TestQueryAliasCycle(t *testing.T) {
+	// Aliases to aliases only occur in trees built by hand.
+	x := &Node{Kind: AliasNode, Value: "x"}
+	y := &Node{Kind: AliasNode, Value: "y", Alias: x}
+	x.Alias = y
+	root := &Node{Kind: MappingNode, Content: []*Node{
+		{Kind: ScalarNode, Value: "a"}, x,
+	}}
+	_, err := root.Query("a")
+	assert.ErrorMatches(t, `yaml: alias chain does not reach a node .*`, err)
+	err = root.SetPath("a.b", &Node{Kind: ScalarNode, Value: "1"})
+	assert.ErrorMatches(t, `yaml: alias chain does not reach a node .*`, err)
+	_, err = Diff(root, &Node{Kind: MappingNode})
+	assert.ErrorMatches(t, `yaml: alias chain does not reach a node .*`, err)
+}
+
+func TestQueryUnresolvedTree(t *testing.T) {
+	// A tree built by hand has no tags; plain << is still a merge key.
+	base := &Node{Kind: MappingNode, Content: []*Node{
+		{Kind: ScalarNode, Value: "a"}, {Kind: ScalarNode, Value: "1"},
+	}}
+	root := &Node{Kind: MappingNode, Content: []*Node{
+		{Kind: ScalarNode, Value: "<<"}, base,
+	}}
+	nodes, err := root.Query("a")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(nodes))
+	assert.Equal(t, "1", nodes[0].Value)
+}
+
+func TestCompilePathErrors(t *testing.T) {
+	tests := []struct {
+		expr, want string
+	}{
+		{"a.", `yaml: invalid path "a\." at offset 2: expected key or '\*' after '\.'`},
+		{"a[", `yaml: invalid path "a\[" at offset 2: unexpected .* in brackets`},
+		{"a[0", `.*expected '\]'`},
+		{"a['x", `.*unterminated string`},
+		{"a[1:2:0]", `.*slice step cannot be zero`},
+		{"a[?(@.b == )]", `.*expected operand`},
+		{"a[?(@.b == nullable)]", `.*offset 11: expected operand`},
+		{"a[?(@.b =~ 'x(')]", `.*invalid pattern: .*`},
+		{"a[?(@.b =~ @.c)]", `.*=~ requires a string pattern`},
+		{"a[?('x')]", `.*literal cannot be used as a condition`},
+		{"a[?(@.b]", `.*expected '\)'`},
+		{"a b", `.*unexpected 'b'`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			_, err := CompilePath(tc.expr)
+			assert.ErrorMatches(t, tc.want, err)
+		})
+	}
+}
+
+func TestMustCompilePath(t *testing.T) {
+	p := MustCompilePath("spec.containers[*]")
+	assert.Equal(t, "spec.containers[*]", p.String())
+	assert.PanicMatches(t, `yaml: invalid path .*`, func() {
+		MustCompilePath("[")
+	})
+}
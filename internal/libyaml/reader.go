@@ -69,6 +69,7 @@ func (parser *Parser) updateBuffer(length int) error {
 
 	// Fill the buffer until it has enough characters.
 	first := true
+	at_limit := false
 	for parser.unread < length {
 
 		// Fill the raw buffer if necessary.
@@ -305,6 +306,19 @@ func (parser *Parser) updateBuffer(length int) error {
 					Mark{Index: parser.offset})
 			}
 
+			// Check the input size limit. Decoding stops at the limit,
+			// and fails once the scanner needs more, so that the error
+			// is reported where the scanner reached it.
+			if max := parser.limits.InputBytes; max > 0 && parser.inputOffset+parser.offset+width > max {
+				if parser.unread < length {
+					return formatReaderError(
+						fmt.Sprintf("exceeded max input size of %d bytes", max),
+						parser.mark)
+				}
+				at_limit = true
+				break inner
+			}
+
 			// Move the raw pointers.
 			parser.raw_buffer_pos += width
 			parser.offset += width
@@ -336,6 +350,9 @@ func (parser *Parser) updateBuffer(length int) error {
 
 			parser.unread++
 		}
+		if at_limit {
+			break
+		}
 
 		// On EOF, put NUL into the buffer and return.
 		if parser.eof {
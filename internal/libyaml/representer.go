@@ -9,9 +9,9 @@ package libyaml
 
 import (
 	"encoding"
+	"math/big"
 	"reflect"
 	"regexp"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -33,6 +33,11 @@ type Representer struct {
 	explicitEnd           bool
 	flowSimpleCollections bool
 	quotePreference       QuoteStyle
+	structOpts            structOptions
+	schema                Schema
+	tagRepresent          TagRepresentFunc
+	keyOrder              KeyOrderFunc
+	discriminators        map[reflect.Type]*discriminator
 }
 
 // NewRepresenter creates a new YAML representer with the given options.
@@ -44,6 +49,11 @@ func NewRepresenter(opts *Options) *Representer {
 		explicitEnd:           opts.ExplicitEnd,
 		flowSimpleCollections: opts.FlowSimpleCollections,
 		quotePreference:       opts.QuotePreference,
+		structOpts:            newStructOptions(opts),
+		schema:                opts.Schema,
+		tagRepresent:          opts.TagRepresent,
+		keyOrder:              opts.MapKeyOrder,
+		discriminators:        opts.Discriminators,
 	}
 }
 
@@ -78,6 +88,16 @@ func (r *Representer) represent(tag string, in reflect.Value) *Node {
 	if !in.IsValid() || in.Kind() == reflect.Pointer && in.IsNil() {
 		return r.nilv()
 	}
+	if in.Kind() == reflect.Interface && r.discriminators != nil && !in.IsNil() {
+		if d := r.discriminators[in.Type()]; d != nil {
+			return r.discriminated(d, in.Elem())
+		}
+	}
+	if r.tagRepresent != nil {
+		if node := r.representTag(in); node != nil {
+			return node
+		}
+	}
 	iface := in.Interface()
 	switch value := iface.(type) {
 	case *Node:
@@ -95,6 +115,16 @@ func (r *Representer) represent(tag string, in reflect.Value) *Node {
 		return r.timev(tag, in.Elem())
 	case time.Duration:
 		return r.stringv(tag, reflect.ValueOf(value.String()))
+	case *big.Int, *big.Float, *big.Rat, Number:
+		return r.bigv(tag, value)
+	case big.Int:
+		return r.bigv(tag, &value)
+	case big.Float:
+		return r.bigv(tag, &value)
+	case big.Rat:
+		return r.bigv(tag, &value)
+	case MapSlice:
+		return r.mapSlicev(tag, value)
 	case Marshaler:
 		v, err := value.MarshalYAML()
 		if err != nil {
@@ -152,7 +182,7 @@ func (r *Representer) mapv(tag string, in reflect.Value) *Node {
 	}
 
 	keys := keyList(in.MapKeys())
-	sort.Sort(keys)
+	r.sortKeys(keys)
 	content := make([]*Node, 0, len(keys)*2)
 	for _, k := range keys {
 		content = append(content, r.represent("", k))
@@ -168,9 +198,9 @@ func (r *Representer) mapv(tag string, in reflect.Value) *Node {
 }
 
 // structv converts a Go struct to a YAML mapping node, handling field tags,
-// omitempty, inline fields, and inline maps.
+// omitempty, omitzero, inline fields, and inline maps.
 func (r *Representer) structv(tag string, in reflect.Value) *Node {
-	sinfo, err := getStructInfo(in.Type())
+	sinfo, err := getStructInfo(in.Type(), r.structOpts)
 	if err != nil {
 		failDump(RepresenterStage, err)
 	}
@@ -195,19 +225,24 @@ func (r *Representer) structv(tag string, in reflect.Value) *Node {
 				continue
 			}
 		}
-		if info.OmitEmpty && isZero(value) {
+		if info.OmitEmpty && isZero(value) || info.OmitZero && isOmitZero(value) {
 			continue
 		}
 		content = append(content, r.represent("", reflect.ValueOf(info.Key)))
 		r.flow = info.Flow
-		content = append(content, r.represent("", value))
+		node := r.represent("", value)
+		if info.String && node.Kind == ScalarNode && node.Tag != nullTag && stringKind(value.Type()) {
+			// The desolver quotes values that would resolve otherwise.
+			node.Tag = strTag
+		}
+		content = append(content, node)
 	}
 	if sinfo.InlineMap >= 0 {
 		m := in.Field(sinfo.InlineMap)
 		if m.Len() > 0 {
 			r.flow = false
 			keys := keyList(m.MapKeys())
-			sort.Sort(keys)
+			r.sortKeys(keys)
 			for _, k := range keys {
 				if _, found := sinfo.FieldsMap[k.String()]; found {
 					failDumpf(RepresenterStage, "cannot have key %q in inlined map: conflicts with struct field", k.String())
@@ -274,8 +309,9 @@ func (r *Representer) stringv(tag string, in reflect.Value) *Node {
 	case tag == "":
 		tag = strTag
 		// Check if this string needs quoting for compatibility
-		// even though it would resolve as !!str
-		needsQuoting = isBase60Float(s) || isOldBool(s) || looksLikeMerge(s)
+		// even though it would resolve as !!str. An explicit schema
+		// leaves quoting to the Desolver.
+		needsQuoting = r.schema == DefaultSchema && (isBase60Float(s) || isOldBool(s) || looksLikeMerge(s))
 	}
 
 	// Set the style based on content
@@ -413,18 +449,10 @@ func (l keyList) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
 // numerically, strings sort with natural number ordering, and mixed types
 // sort by kind.
 func (l keyList) Less(i, j int) bool {
-	a := l[i]
-	b := l[j]
+	a := keyValue(l[i])
+	b := keyValue(l[j])
 	ak := a.Kind()
 	bk := b.Kind()
-	for (ak == reflect.Interface || ak == reflect.Pointer) && !a.IsNil() {
-		a = a.Elem()
-		ak = a.Kind()
-	}
-	for (bk == reflect.Interface || bk == reflect.Pointer) && !b.IsNil() {
-		b = b.Elem()
-		bk = b.Kind()
-	}
 	af, aok := keyFloat(a)
 	bf, bok := keyFloat(b)
 	if aok && bok {
@@ -439,7 +467,22 @@ func (l keyList) Less(i, j int) bool {
 	if ak != reflect.String || bk != reflect.String {
 		return ak < bk
 	}
-	ar, br := []rune(a.String()), []rune(b.String())
+	return naturalLess(a.String(), b.String())
+}
+
+// keyValue returns the value a map key holds, through interfaces and
+// pointers.
+func keyValue(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// naturalLess compares strings with natural number ordering, so that
+// "a2" sorts before "a10".
+func naturalLess(a, b string) bool {
+	ar, br := []rune(a), []rune(b)
 	digits := false
 	for i := 0; i < len(ar) && i < len(br); i++ {
 		if ar[i] == br[i] {
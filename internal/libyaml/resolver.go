@@ -12,6 +12,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"math"
+	"math/big"
 	"regexp"
 	"strconv"
 	"strings"
@@ -27,12 +28,19 @@ type resolveMapItem struct {
 
 // Resolver handles tag resolution for YAML nodes.
 type Resolver struct {
-	opts *Options
+	opts        *Options
+	schema      Schema
+	implicitTag func(value string) string
 }
 
 // NewResolver creates a new Resolver with the given options.
 func NewResolver(opts *Options) *Resolver {
-	return &Resolver{opts: opts}
+	r := &Resolver{opts: opts}
+	if opts != nil {
+		r.schema = opts.Schema
+		r.implicitTag = opts.ImplicitTag
+	}
+	return r
 }
 
 // Resolve walks the node tree and resolves tags for untagged nodes.
@@ -41,6 +49,7 @@ func NewResolver(opts *Options) *Resolver {
 // - Default sequences to !!seq
 // - Default mappings to !!map
 // - Resolve plain scalars to implicit types (int, float, bool, null, timestamp)
+// - Resolve plain scalars to application tags of a resolver plugin
 func (r *Resolver) Resolve(n *Node) {
 	if n == nil {
 		return
@@ -54,7 +63,7 @@ func (r *Resolver) Resolve(n *Node) {
 				n.Tag = strTag
 			} else {
 				// Plain scalars: resolve type from value
-				n.Tag, _ = resolve("", n.Value)
+				n.Tag = resolvePlain(r.schema, r.implicitTag, n.Value)
 			}
 		}
 
@@ -84,12 +93,46 @@ func (r *Resolver) Resolve(n *Node) {
 	}
 }
 
+// resolvePlain returns the tag of a plain scalar under schema. Values the
+// schema reads as strings are offered to implicit, if set, which may give
+// them an application tag.
+func resolvePlain(schema Schema, implicit func(string) string, value string) string {
+	tag, _ := schema.resolve("", value)
+	if tag == strTag && implicit != nil {
+		if itag := implicit(value); itag != "" {
+			return itag
+		}
+	}
+	return tag
+}
+
+// ChainImplicitTagFuncs returns an implicit tag function that tags plain
+// scalars with first, then with then if first left them strings.
+func ChainImplicitTagFuncs(first, then func(value string) string) func(value string) string {
+	if first == nil {
+		return then
+	}
+	return func(value string) string {
+		if tag := first(value); tag != "" {
+			return tag
+		}
+		return then(value)
+	}
+}
+
 // resolve determines the YAML tag and Go value for a scalar string.
 // It takes a tag hint and the scalar string value, and returns the resolved
 // tag and the corresponding Go value (int, float, bool, [time.Time], etc.).
 // If the tag is already specified and non-resolvable, it returns the input
 // unchanged.
 func resolve(tag string, in string) (rtag string, out any) {
+	return resolveNumbers(tag, in, false)
+}
+
+// resolveNumbers is like resolve. When bigInts is set, integers too large
+// for int64 and uint64 resolve to *big.Int rather than to a float or a
+// string.
+func resolveNumbers(tag string, in string, bigInts bool) (rtag string, out any) {
 	tag = shortTag(tag)
 	if !resolvableTag(tag) {
 		return tag, in
@@ -110,6 +153,10 @@ func resolve(tag string, in string) (rtag string, out any) {
 					rtag = floatTag
 					out = float64(v)
 					return
+				case *big.Int:
+					rtag = floatTag
+					out, _ = new(big.Float).SetInt(v).Float64()
+					return
 				}
 			}
 		}
@@ -170,6 +217,11 @@ func resolve(tag string, in string) (rtag string, out any) {
 			if err == nil {
 				return intTag, uintv
 			}
+			if bigInts {
+				if bigv, ok := new(big.Int).SetString(plain, 0); ok {
+					return intTag, bigv
+				}
+			}
 			if yamlStyleFloat.MatchString(plain) {
 				floatv, err := strconv.ParseFloat(plain, 64)
 				if err == nil {
@@ -183,6 +235,14 @@ func resolve(tag string, in string) (rtag string, out any) {
 	return strTag, in
 }
 
+// tryResolve is like resolve, but returns an error instead of failing when
+// an explicit tag does not match the value.
+func tryResolve(tag string, in string) (rtag string, out any, err error) {
+	defer handleErr(&err)
+	rtag, out = resolve(tag, in)
+	return rtag, out, nil
+}
+
 // resolveTable provides a fast lookup table for initial character-based
 // classification during tag resolution.
 // resolveMap maps specific scalar strings to their resolved values and tags.
@@ -1700,6 +1700,9 @@ func (parser *Parser) scanBlockScalar(token *Token, literal bool) error {
 		// Consume the current line.
 		for !isBreakOrZero(parser.buffer, parser.buffer_pos) {
 			s = parser.read(s)
+			if err := parser.checkScalarLength(s, start_mark); err != nil {
+				return err
+			}
 			if parser.unread < 1 {
 				if err := parser.updateBuffer(1); err != nil {
 					return err
@@ -1730,6 +1733,10 @@ func (parser *Parser) scanBlockScalar(token *Token, literal bool) error {
 		s = append(s, trailing_breaks...)
 	}
 
+	if err := parser.checkScalarLength(s, start_mark); err != nil {
+		return err
+	}
+
 	// Create a token.
 	*token = Token{
 		Type:      SCALAR_TOKEN,
@@ -2287,6 +2294,9 @@ func (parser *Parser) scanFlowScalar(token *Token, single bool) error {
 				// It is a non-escaped non-blank character.
 				s = parser.read(s)
 			}
+			if err := parser.checkScalarLength(s, start_mark); err != nil {
+				return err
+			}
 			if parser.unread < 2 {
 				if err := parser.updateBuffer(2); err != nil {
 					return err
@@ -2368,6 +2378,10 @@ func (parser *Parser) scanFlowScalar(token *Token, single bool) error {
 	parser.skip()
 	end_mark := parser.mark
 
+	if err := parser.checkScalarLength(s, start_mark); err != nil {
+		return err
+	}
+
 	// Create a token.
 	*token = Token{
 		Type:      SCALAR_TOKEN,
@@ -2511,6 +2525,9 @@ func (parser *Parser) scanPlainScalar(token *Token) error {
 
 			// Copy the character.
 			s = parser.read(s)
+			if err := parser.checkScalarLength(s, start_mark); err != nil {
+				return err
+			}
 
 			end_mark = parser.mark
 			if parser.unread < 2 {
@@ -2576,6 +2593,10 @@ func (parser *Parser) scanPlainScalar(token *Token) error {
 		}
 	}
 
+	if err := parser.checkScalarLength(s, start_mark); err != nil {
+		return err
+	}
+
 	// Create a token.
 	*token = Token{
 		Type:      SCALAR_TOKEN,
@@ -0,0 +1,338 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Resolution schemas for plain scalars.
+//
+// A schema decides which tag an untagged plain scalar resolves to. The
+// default schema is the historical go-yaml mix of YAML 1.1 and 1.2 rules
+// implemented by resolve; the others follow the schemas of the YAML 1.2
+// specification and the YAML 1.1 type repository.
+
+package libyaml
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Schema selects the rules used to resolve the tags of plain scalars when
+// loading, and to decide which strings need quoting when dumping.
+type Schema int
+
+const (
+	// DefaultSchema is the go-yaml schema: the YAML 1.2 Core schema,
+	// extended with timestamps, binary (0b) integers, legacy octal
+	// integers (0755) and underscores in numbers.
+	DefaultSchema Schema = iota
+
+	// CoreSchema is the YAML 1.2 Core schema. Merge keys (<<) are
+	// recognized as well.
+	CoreSchema
+
+	// JSONSchema is the YAML 1.2 JSON schema: only null, true, false and
+	// JSON numbers are resolved, and every other plain scalar is a string.
+	JSONSchema
+
+	// FailsafeSchema is the YAML 1.2 Failsafe schema: every plain scalar
+	// is a string.
+	FailsafeSchema
+
+	// YAML11Schema is the YAML 1.1 schema: yes/no/on/off booleans,
+	// base 60 numbers, 0-prefixed octals, timestamps and merge keys.
+	YAML11Schema
+)
+
+// String returns the name of the schema.
+func (s Schema) String() string {
+	switch s {
+	case DefaultSchema:
+		return "default"
+	case CoreSchema:
+		return "core"
+	case JSONSchema:
+		return "json"
+	case FailsafeSchema:
+		return "failsafe"
+	case YAML11Schema:
+		return "yaml1.1"
+	}
+	return fmt.Sprintf("Schema(%d)", int(s))
+}
+
+// versionSchema returns the schema selected by a %YAML version directive,
+// and whether the version selects one.
+func versionSchema(v *VersionDirective) (Schema, bool) {
+	if v == nil || v.Major() != 1 {
+		return 0, false
+	}
+	if v.Minor() == 1 {
+		return YAML11Schema, true
+	}
+	return CoreSchema, true
+}
+
+// resolve determines the YAML tag and Go value for a scalar string under
+// the schema, like the package-level resolve does for DefaultSchema.
+//
+// Explicitly tagged values the schema doesn't recognize are read with the
+// default rules, so that "!!int 0x1F" loads under any schema.
+func (s Schema) resolve(tag, in string) (string, any) {
+	return s.resolveNumbers(tag, in, false)
+}
+
+// resolveNumbers is like resolve. When bigInts is set, integers too large
+// for int64 and uint64 resolve to *big.Int rather than to a float or a
+// string.
+func (s Schema) resolveNumbers(tag, in string, bigInts bool) (string, any) {
+	if s == DefaultSchema {
+		return resolveNumbers(tag, in, bigInts)
+	}
+	tag = shortTag(tag)
+	if !resolvableTag(tag) {
+		return tag, in
+	}
+	switch tag {
+	case strTag:
+		return strTag, in
+	case timestampTag:
+		if t, ok := parseTimestamp(in); ok {
+			return timestampTag, t
+		}
+	}
+
+	var rtag string
+	var out any
+	switch s {
+	case CoreSchema:
+		rtag, out = resolveCore(in, bigInts)
+	case JSONSchema:
+		rtag, out = resolveJSON(in, bigInts)
+	case YAML11Schema:
+		rtag, out = resolveYAML11(in, bigInts)
+	default:
+		rtag, out = strTag, in
+	}
+	switch {
+	case tag == "" || tag == rtag:
+		return rtag, out
+	case tag == floatTag && rtag == intTag:
+		return floatTag, intToFloat(out)
+	}
+	return resolveNumbers(tag, in, bigInts)
+}
+
+// intToFloat converts a resolved integer to float64.
+func intToFloat(v any) float64 {
+	switch v := v.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f
+	}
+	return 0
+}
+
+// resolveInt returns the Go value of an integer, choosing the smallest of
+// int, int64 and uint64 that holds it, or a *big.Int when bigInts is set.
+// It reports false when the integer overflows or the digits are not valid
+// in base.
+func resolveInt(digits string, base int, negative, bigInts bool) (any, bool) {
+	if negative {
+		digits = "-" + digits
+	}
+	if v, err := strconv.ParseInt(digits, base, 64); err == nil {
+		if v == int64(int(v)) {
+			return int(v), true
+		}
+		return v, true
+	}
+	if v, err := strconv.ParseUint(digits, base, 64); err == nil {
+		return v, true
+	}
+	if bigInts {
+		if v, ok := new(big.Int).SetString(digits, base); ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+var (
+	coreInt   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	coreOct   = regexp.MustCompile(`^0o[0-7]+$`)
+	coreHex   = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+	coreFloat = regexp.MustCompile(`^[-+]?(?:\.[0-9]+|[0-9]+(?:\.[0-9]*)?)(?:[eE][-+]?[0-9]+)?$`)
+
+	jsonInt   = regexp.MustCompile(`^-?(?:0|[1-9][0-9]*)$`)
+	jsonFloat = regexp.MustCompile(`^-?(?:0|[1-9][0-9]*)(?:\.[0-9]*)?(?:[eE][-+]?[0-9]+)?$`)
+
+	yaml11Bin     = regexp.MustCompile(`^[-+]?0b[01_]+$`)
+	yaml11Oct     = regexp.MustCompile(`^[-+]?0[0-7_]+$`)
+	yaml11Dec     = regexp.MustCompile(`^[-+]?(?:0|[1-9][0-9_]*)$`)
+	yaml11Hex     = regexp.MustCompile(`^[-+]?0x[0-9a-fA-F_]+$`)
+	yaml11Int60   = regexp.MustCompile(`^[-+]?[1-9][0-9_]*(?::[0-5]?[0-9])+$`)
+	yaml11Float   = regexp.MustCompile(`^[-+]?(?:[0-9][0-9_]*)?\.[0-9.]*(?:[eE][-+][0-9]+)?$`)
+	yaml11Float60 = regexp.MustCompile(`^[-+]?[0-9][0-9_]*(?::[0-5]?[0-9])+\.[0-9_]*$`)
+)
+
+// resolveCore resolves a plain scalar with the YAML 1.2 Core schema.
+func resolveCore(in string, bigInts bool) (string, any) {
+	switch in {
+	case "", "~", "null", "Null", "NULL":
+		return nullTag, nil
+	case "true", "True", "TRUE":
+		return boolTag, true
+	case "false", "False", "FALSE":
+		return boolTag, false
+	case ".nan", ".NaN", ".NAN":
+		return floatTag, math.NaN()
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return floatTag, math.Inf(+1)
+	case "-.inf", "-.Inf", "-.INF":
+		return floatTag, math.Inf(-1)
+	case "<<":
+		return mergeTag, in
+	}
+	var v any
+	var ok bool
+	switch {
+	case coreInt.MatchString(in):
+		digits := in
+		if in[0] == '-' || in[0] == '+' {
+			digits = in[1:]
+		}
+		v, ok = resolveInt(digits, 10, in[0] == '-', bigInts)
+	case coreOct.MatchString(in):
+		v, ok = resolveInt(in[2:], 8, false, bigInts)
+	case coreHex.MatchString(in):
+		v, ok = resolveInt(in[2:], 16, false, bigInts)
+	}
+	if ok {
+		return intTag, v
+	}
+	if coreFloat.MatchString(in) {
+		if f, err := strconv.ParseFloat(in, 64); err == nil {
+			return floatTag, f
+		}
+	}
+	return strTag, in
+}
+
+// resolveJSON resolves a plain scalar with the YAML 1.2 JSON schema.
+func resolveJSON(in string, bigInts bool) (string, any) {
+	switch in {
+	case "null":
+		return nullTag, nil
+	case "true":
+		return boolTag, true
+	case "false":
+		return boolTag, false
+	}
+	if jsonInt.MatchString(in) {
+		if v, ok := resolveInt(strings.TrimPrefix(in, "-"), 10, in[0] == '-', bigInts); ok {
+			return intTag, v
+		}
+	}
+	if jsonFloat.MatchString(in) {
+		if f, err := strconv.ParseFloat(in, 64); err == nil {
+			return floatTag, f
+		}
+	}
+	return strTag, in
+}
+
+// resolveYAML11 resolves a plain scalar with the YAML 1.1 types.
+func resolveYAML11(in string, bigInts bool) (string, any) {
+	switch in {
+	case "", "~", "null", "Null", "NULL":
+		return nullTag, nil
+	case "y", "Y", "yes", "Yes", "YES", "true", "True", "TRUE", "on", "On", "ON":
+		return boolTag, true
+	case "n", "N", "no", "No", "NO", "false", "False", "FALSE", "off", "Off", "OFF":
+		return boolTag, false
+	case ".nan", ".NaN", ".NAN":
+		return floatTag, math.NaN()
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return floatTag, math.Inf(+1)
+	case "-.inf", "-.Inf", "-.INF":
+		return floatTag, math.Inf(-1)
+	case "<<":
+		return mergeTag, in
+	}
+	if t, ok := parseTimestamp(in); ok {
+		return timestampTag, t
+	}
+
+	negative := in[0] == '-'
+	digits := strings.ReplaceAll(in, "_", "")
+	if in[0] == '-' || in[0] == '+' {
+		digits = digits[1:]
+	}
+	var v any
+	var ok bool
+	switch {
+	case yaml11Bin.MatchString(in):
+		v, ok = resolveInt(digits[2:], 2, negative, bigInts)
+	case yaml11Hex.MatchString(in):
+		v, ok = resolveInt(digits[2:], 16, negative, bigInts)
+	case yaml11Oct.MatchString(in):
+		v, ok = resolveInt(digits[1:], 8, negative, bigInts)
+	case yaml11Dec.MatchString(in):
+		v, ok = resolveInt(digits, 10, negative, bigInts)
+	case yaml11Int60.MatchString(in):
+		var n int64
+		n, ok = parseBase60(digits)
+		if negative {
+			n = -n
+		}
+		v = int(n)
+		if int64(int(n)) != n {
+			v = n
+		}
+	}
+	if ok {
+		return intTag, v
+	}
+
+	if yaml11Float.MatchString(in) {
+		if f, err := strconv.ParseFloat(strings.ReplaceAll(in, "_", ""), 64); err == nil {
+			return floatTag, f
+		}
+	}
+	if yaml11Float60.MatchString(in) {
+		dot := strings.IndexByte(digits, '.')
+		if whole, ok := parseBase60(digits[:dot]); ok {
+			frac, err := strconv.ParseFloat("0"+digits[dot:], 64)
+			if err == nil {
+				f := float64(whole) + frac
+				if negative {
+					f = -f
+				}
+				return floatTag, f
+			}
+		}
+	}
+	return strTag, in
+}
+
+// parseBase60 parses colon-separated base 60 digits, such as 1:30:00.
+func parseBase60(s string) (int64, bool) {
+	var n int64
+	for _, part := range strings.Split(s, ":") {
+		d, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n > math.MaxInt64/60 {
+			return 0, false
+		}
+		n = n*60 + d
+	}
+	return n, true
+}
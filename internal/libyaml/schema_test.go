@@ -0,0 +1,148 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for the resolution schemas.
+
+package libyaml
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+func TestSchemaResolve(t *testing.T) {
+	tests := []struct {
+		schema Schema
+		in     string
+		tag    string
+		value  any
+	}{
+		{CoreSchema, "", nullTag, nil},
+		{CoreSchema, "~", nullTag, nil},
+		{CoreSchema, "True", boolTag, true},
+		{CoreSchema, "yes", strTag, "yes"},
+		{CoreSchema, "0123", intTag, 123},
+		{CoreSchema, "-0", intTag, 0},
+		{CoreSchema, "+12", intTag, 12},
+		{CoreSchema, "0o17", intTag, 15},
+		{CoreSchema, "0x1F", intTag, 31},
+		{CoreSchema, "0b101", strTag, "0b101"},
+		{CoreSchema, "1_000", strTag, "1_000"},
+		{CoreSchema, "18446744073709551615", intTag, uint64(math.MaxUint64)},
+		{CoreSchema, "1e3", floatTag, 1000.0},
+		{CoreSchema, ".5", floatTag, 0.5},
+		{CoreSchema, "-.inf", floatTag, math.Inf(-1)},
+		{CoreSchema, "2001-12-14", strTag, "2001-12-14"},
+		{CoreSchema, "<<", mergeTag, "<<"},
+
+		{JSONSchema, "null", nullTag, nil},
+		{JSONSchema, "", strTag, ""},
+		{JSONSchema, "~", strTag, "~"},
+		{JSONSchema, "true", boolTag, true},
+		{JSONSchema, "True", strTag, "True"},
+		{JSONSchema, "-12", intTag, -12},
+		{JSONSchema, "012", strTag, "012"},
+		{JSONSchema, "+12", strTag, "+12"},
+		{JSONSchema, "1.5e-3", floatTag, 0.0015},
+		{JSONSchema, ".inf", strTag, ".inf"},
+
+		{FailsafeSchema, "null", strTag, "null"},
+		{FailsafeSchema, "12", strTag, "12"},
+
+		{YAML11Schema, "yes", boolTag, true},
+		{YAML11Schema, "Off", boolTag, false},
+		{YAML11Schema, "n", boolTag, false},
+		{YAML11Schema, "0755", intTag, 493},
+		{YAML11Schema, "-0b1_01", intTag, -5},
+		{YAML11Schema, "0x_1F", intTag, 31},
+		{YAML11Schema, "1_000", intTag, 1000},
+		{YAML11Schema, "0o17", strTag, "0o17"},
+		{YAML11Schema, "190:20:30", intTag, 685230},
+		{YAML11Schema, "-1:30", intTag, -90},
+		{YAML11Schema, "1:30.5", floatTag, 90.5},
+		{YAML11Schema, "1.5", floatTag, 1.5},
+		{YAML11Schema, "1e3", strTag, "1e3"},
+		{YAML11Schema, "09", strTag, "09"},
+		{YAML11Schema, "2001-12-14", timestampTag, time.Date(2001, 12, 14, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range tests {
+		tag, value := tc.schema.resolve("", tc.in)
+		assert.Equalf(t, tc.tag, tag, "%s %q", tc.schema, tc.in)
+		assert.DeepEqualf(t, tc.value, value, "%s %q", tc.schema, tc.in)
+	}
+}
+
+func TestSchemaExplicitTags(t *testing.T) {
+	// Explicit tags the schema doesn't recognize use the default rules.
+	tag, value := FailsafeSchema.resolve("!!int", "0x1F")
+	assert.Equal(t, intTag, tag)
+	assert.Equal(t, 31, value)
+
+	tag, value = JSONSchema.resolve("!!float", "3")
+	assert.Equal(t, floatTag, tag)
+	assert.Equal(t, 3.0, value)
+
+	tag, value = CoreSchema.resolve("!!str", "true")
+	assert.Equal(t, strTag, tag)
+	assert.Equal(t, "true", value)
+
+	assert.PanicMatches(t, "cannot construct !!str `yes` as a !!bool", func() {
+		CoreSchema.resolve("!!bool", "yes")
+	})
+}
+
+func TestLoadWithSchema(t *testing.T) {
+	const src = "a: yes\nb: 0755\nc: 1:30\n"
+	var v map[string]any
+	assert.NoError(t, Load([]byte(src), &v))
+	assert.DeepEqual(t, map[string]any{"a": "yes", "b": 493, "c": "1:30"}, v)
+
+	v = nil
+	assert.NoError(t, Load([]byte(src), &v, WithSchema(YAML11Schema)))
+	assert.DeepEqual(t, map[string]any{"a": true, "b": 493, "c": 90}, v)
+
+	v = nil
+	assert.NoError(t, Load([]byte(src), &v, WithSchema(CoreSchema)))
+	assert.DeepEqual(t, map[string]any{"a": "yes", "b": 755, "c": "1:30"}, v)
+
+	var s struct{ A bool }
+	assert.NoError(t, Load([]byte("a: on\n"), &s, WithSchema(YAML11Schema)))
+	assert.True(t, s.A)
+
+	// The %YAML directive selects the schema of each document.
+	stream := "%YAML 1.1\n---\na: yes\nb: 1\n...\n---\na: yes\nb: 1\n"
+	var docs []map[string]any
+	assert.NoError(t, Load([]byte(stream), &docs, WithAllDocuments(), WithSchemaFromVersion(), WithSchema(FailsafeSchema)))
+	assert.DeepEqual(t, []map[string]any{{"a": true, "b": 1}, {"a": "yes", "b": "1"}}, docs)
+
+	docs = nil
+	assert.NoError(t, Load([]byte(stream), &docs, WithAllDocuments()))
+	assert.DeepEqual(t, []map[string]any{{"a": "yes", "b": 1}, {"a": "yes", "b": 1}}, docs)
+
+	stream = "%YAML 1.2\n---\na: yes\nb: 0o17\n"
+	docs = nil
+	assert.NoError(t, Load([]byte(stream), &docs, WithAllDocuments(), WithSchemaFromVersion(), WithSchema(YAML11Schema)))
+	assert.DeepEqual(t, []map[string]any{{"a": "yes", "b": 15}}, docs)
+
+	_, err := ApplyOptions(WithSchema(Schema(42)))
+	assert.ErrorMatches(t, "invalid Schema value: 42", err)
+}
+
+func TestDumpWithSchema(t *testing.T) {
+	v := map[string]any{"s": "yes", "t": "True", "b": true, "n": nil}
+
+	out, err := Dump(v, WithSchema(YAML11Schema))
+	assert.NoError(t, err)
+	assert.Equal(t, "b: true\n'n': null\ns: 'yes'\nt: 'True'\n", string(out))
+
+	out, err = Dump(v, WithSchema(JSONSchema))
+	assert.NoError(t, err)
+	assert.Equal(t, "b: true\nn: null\ns: yes\nt: True\n", string(out))
+
+	out, err = Dump(v, WithSchema(FailsafeSchema))
+	assert.NoError(t, err)
+	assert.Equal(t, "b: !!bool true\nn: !!null null\ns: yes\nt: True\n", string(out))
+}
@@ -25,6 +25,9 @@ type Serializer struct {
 	flowSimpleCollections bool
 	quotePreference       QuoteStyle
 	doneInit              bool
+	eventHook             EventFunc
+	events                *[]Event // collects the events instead of emitting them
+	opts                  *Options
 }
 
 // NewSerializer creates a new Serializer with the given options.
@@ -55,6 +58,8 @@ func NewSerializer(w io.Writer, opts *Options) *Serializer {
 		explicitEnd:           opts.ExplicitEnd,
 		flowSimpleCollections: opts.FlowSimpleCollections,
 		quotePreference:       opts.QuotePreference,
+		eventHook:             opts.EventHook,
+		opts:                  opts,
 	}
 }
 
@@ -83,6 +88,8 @@ func (s *Serializer) init() {
 
 // Finish completes serialization by emitting a STREAM_END event.
 func (s *Serializer) Finish() {
+	// A stream without documents still needs its start event.
+	s.init()
 	s.Emitter.OpenEnded = false
 	s.emit(NewStreamEndEvent())
 }
@@ -229,7 +236,17 @@ func (s *Serializer) node(node *Node, tail string) {
 
 // emit sends an event to the underlying emitter.
 func (s *Serializer) emit(event Event) {
-	s.must(s.Emitter.Emit(&event))
+	if s.events != nil {
+		*s.events = append(*s.events, event)
+		return
+	}
+	if s.eventHook == nil {
+		s.must(s.Emitter.Emit(&event))
+		return
+	}
+	for _, event := range s.hookEvent(event) {
+		s.must(s.Emitter.Emit(&event))
+	}
 }
 
 // must panics if the given error is non-nil, routing to the appropriate stage.
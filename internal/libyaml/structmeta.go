@@ -6,7 +6,8 @@
 //
 // This file analyzes Go struct types to build mappings between YAML keys and
 // struct fields. It parses struct tags like `yaml:"name,omitempty,flow,inline"`
-// and caches the results for efficient repeated access.
+// or `yaml:"name,omitzero,required,alias=old,default=value"` and caches the
+// results for efficient repeated access.
 //
 // Used by:
 //   - Constructor: maps YAML keys to struct fields when unmarshaling
@@ -25,8 +26,87 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"unicode"
+	"unicode/utf8"
 )
 
+// FieldNaming derives the YAML key of a struct field from its Go name when
+// the field tag does not set a key.
+type FieldNaming int
+
+const (
+	LowerCase  FieldNaming = iota // MaxRetries becomes maxretries (default)
+	SnakeCase                     // MaxRetries becomes max_retries
+	KebabCase                     // MaxRetries becomes max-retries
+	CamelCase                     // MaxRetries becomes maxRetries
+	PascalCase                    // MaxRetries stays MaxRetries
+)
+
+// fieldKey returns the key of a Go field name under the naming policy.
+//
+// Names are split into words before each upper case letter that follows a
+// lower case letter or digit, and before the last letter of an upper case
+// run followed by a lower case letter, so HTTPServerID is split into HTTP,
+// Server and ID. CamelCase lower-cases the first word and keeps the others.
+func (f FieldNaming) fieldKey(name string) string {
+	switch f {
+	case LowerCase:
+		return strings.ToLower(name)
+	case PascalCase:
+		return name
+	}
+	words := splitFieldName(name)
+	var b strings.Builder
+	for i, word := range words {
+		switch f {
+		case SnakeCase, KebabCase:
+			if i > 0 {
+				if f == SnakeCase {
+					b.WriteByte('_')
+				} else {
+					b.WriteByte('-')
+				}
+			}
+			b.WriteString(strings.ToLower(word))
+		case CamelCase:
+			if i == 0 {
+				b.WriteString(strings.ToLower(word))
+			} else {
+				b.WriteString(word)
+			}
+		}
+	}
+	return b.String()
+}
+
+// splitFieldName splits a Go identifier into words. Underscores separate
+// words and are dropped.
+func splitFieldName(name string) []string {
+	var words []string
+	start := 0
+	prev := rune(0)
+	for i, r := range name {
+		next, _ := utf8.DecodeRuneInString(name[i+utf8.RuneLen(r):])
+		switch {
+		case r == '_':
+			if i > start {
+				words = append(words, name[start:i])
+			}
+			start = i + 1
+		case unicode.IsUpper(r) && i > start &&
+			(unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				unicode.IsUpper(prev) && unicode.IsLower(next)):
+			words = append(words, name[start:i])
+			start = i
+		}
+		prev = r
+	}
+	if start < len(name) {
+		words = append(words, name[start:])
+	}
+	return words
+}
+
 // structInfo holds cached information about a struct's YAML-relevant fields.
 type structInfo struct {
 	FieldsMap  map[string]fieldInfo
@@ -39,6 +119,36 @@ type structInfo struct {
 	// InlineConstructors holds indexes to inlined fields that
 	// contain constructor values.
 	InlineConstructors [][]int
+
+	// PresenceFields lists the fields with a ,required or ,default
+	// option, which are checked when their key is missing.
+	PresenceFields []fieldInfo
+
+	// AliasMap maps the ,alias= keys of fields to their field Id.
+	AliasMap map[string]int
+
+	// FoldMap maps the lower-cased keys and aliases of fields to the key
+	// or alias itself, for case-insensitive matching. Keys that differ only
+	// in case are left out.
+	FoldMap map[string]string
+}
+
+// field returns the field stored under key, which may be an alias of its
+// key. With fold set, key is matched case-insensitively when it matches
+// nothing exactly. alias reports whether key matched an alias.
+func (sinfo *structInfo) field(key string, fold bool) (info fieldInfo, alias, ok bool) {
+	if info, ok := sinfo.FieldsMap[key]; ok {
+		return info, false, true
+	}
+	if id, ok := sinfo.AliasMap[key]; ok {
+		return sinfo.FieldsList[id], true, true
+	}
+	if fold {
+		if k, ok := sinfo.FoldMap[strings.ToLower(key)]; ok {
+			return sinfo.field(k, false)
+		}
+	}
+	return fieldInfo{}, false, false
 }
 
 // fieldInfo holds information about a single struct field.
@@ -46,6 +156,7 @@ type fieldInfo struct {
 	Key       string
 	Num       int
 	OmitEmpty bool
+	OmitZero  bool
 	Flow      bool
 	// Id holds the unique field identifier, so we can cheaply
 	// check for field duplicates without maintaining an extra map.
@@ -53,13 +164,46 @@ type fieldInfo struct {
 
 	// Inline holds the field index if the field is part of an inlined struct.
 	Inline []int
+
+	// Required reports whether the key must be present when decoding.
+	Required bool
+
+	// Default holds the value decoded into the field when its key is
+	// missing, or nil.
+	Default *Node
+
+	// Aliases holds the alternative keys accepted when decoding.
+	Aliases []string
+
+	// String reports whether a number or bool is written as a string, as
+	// set by the ,string option of a json tag.
+	String bool
+}
+
+// structOptions holds the options that change how struct fields map to
+// YAML keys.
+type structOptions struct {
+	naming   FieldNaming // Naming of fields without a key in their tag
+	jsonTags bool        // Read json tags of fields without a yaml tag
+}
+
+// newStructOptions returns the struct options set in opts.
+func newStructOptions(opts *Options) structOptions {
+	return structOptions{naming: opts.FieldNaming, jsonTags: opts.JSONTags}
+}
+
+// structKey identifies the cached information of a struct type under a set
+// of struct options.
+type structKey struct {
+	typ  reflect.Type
+	opts structOptions
 }
 
 // structMap caches struct reflection information.
 // fieldMapMutex protects access to structMap.
 // constructorType holds the [reflect.Type] for the constructor interface.
 var (
-	structMap       = make(map[reflect.Type]*structInfo)
+	structMap       = make(map[structKey]*structInfo)
 	fieldMapMutex   sync.RWMutex
 	constructorType reflect.Type
 )
@@ -123,9 +267,11 @@ func isYAMLNodePkg(pkg string) bool {
 
 // getStructInfo returns cached information about a struct type's fields.
 // It parses struct tags and builds a map of field names to field info.
-func getStructInfo(st reflect.Type) (*structInfo, error) {
+// Fields without a key in their tag are named according to opts.naming.
+func getStructInfo(st reflect.Type, opts structOptions) (*structInfo, error) {
+	key := structKey{st, opts}
 	fieldMapMutex.RLock()
-	sinfo, found := structMap[st]
+	sinfo, found := structMap[key]
 	fieldMapMutex.RUnlock()
 	if found {
 		return sinfo, nil
@@ -151,22 +297,56 @@ func getStructInfo(st reflect.Type) (*structInfo, error) {
 		if tag == "-" {
 			continue
 		}
+		if tag == "" && opts.jsonTags {
+			var skip bool
+			tag, info.String, skip = jsonTag(field)
+			if skip {
+				continue
+			}
+		}
 
 		inline := false
 		fields := strings.Split(tag, ",")
 		if len(fields) > 1 {
-			for _, flag := range fields[1:] {
-				switch flag {
-				case "omitempty":
+		flags:
+			for j, flag := range fields[1:] {
+				switch {
+				case flag == "omitempty":
 					info.OmitEmpty = true
-				case "flow":
+				case flag == "omitzero":
+					info.OmitZero = true
+				case flag == "flow":
 					info.Flow = true
-				case "inline":
+				case flag == "inline":
 					inline = true
+				case flag == "required":
+					info.Required = true
+				case strings.HasPrefix(flag, "alias="):
+					alias := flag[len("alias="):]
+					if alias == "" {
+						return nil, fmt.Errorf("empty alias in tag %q of type %s", tag, st)
+					}
+					info.Aliases = append(info.Aliases, alias)
+				case strings.HasPrefix(flag, "default="):
+					// The default value runs to the end of the tag, so
+					// that it may contain commas.
+					value := strings.Join(fields[1+j:], ",")[len("default="):]
+					def, err := parseDefault(value)
+					if err != nil {
+						return nil, fmt.Errorf("invalid default in tag %q of type %s: %v", tag, st, err)
+					}
+					info.Default = def
+					break flags
 				default:
 					return nil, fmt.Errorf("unsupported flag %q in tag %q of type %s", flag, tag, st)
 				}
 			}
+			if info.Required && info.Default != nil {
+				return nil, fmt.Errorf("flags required and default both set in tag %q of type %s", tag, st)
+			}
+			if inline && (info.Required || info.Default != nil || info.Aliases != nil) {
+				return nil, fmt.Errorf("flags required, default and alias cannot be used with inline in tag %q of type %s", tag, st)
+			}
 			tag = fields[0]
 		}
 
@@ -192,7 +372,7 @@ func getStructInfo(st reflect.Type) (*structInfo, error) {
 				if reflect.PointerTo(ftype).Implements(constructorType) || hasConstructYAMLMethod(reflect.PointerTo(ftype)) {
 					inlineConstructors = append(inlineConstructors, []int{i})
 				} else {
-					sinfo, err := getStructInfo(ftype)
+					sinfo, err := getStructInfo(ftype, opts)
 					if err != nil {
 						return nil, err
 					}
@@ -223,7 +403,7 @@ func getStructInfo(st reflect.Type) (*structInfo, error) {
 		if tag != "" {
 			info.Key = tag
 		} else {
-			info.Key = strings.ToLower(field.Name)
+			info.Key = opts.naming.fieldKey(field.Name)
 		}
 
 		if _, found = fieldsMap[info.Key]; found {
@@ -236,15 +416,113 @@ func getStructInfo(st reflect.Type) (*structInfo, error) {
 		fieldsMap[info.Key] = info
 	}
 
+	var presenceFields []fieldInfo
+	aliasMap := make(map[string]int)
+	foldMap := make(map[string]string)
+	foldIds := make(map[string]int)
+	addFold := func(key string, id int) {
+		k := strings.ToLower(key)
+		if prev, found := foldIds[k]; found {
+			if prev != id {
+				delete(foldMap, k)
+			}
+			return
+		}
+		foldIds[k] = id
+		foldMap[k] = key
+	}
+	for _, finfo := range fieldsList {
+		if finfo.Required || finfo.Default != nil {
+			presenceFields = append(presenceFields, finfo)
+		}
+		addFold(finfo.Key, finfo.Id)
+		for _, alias := range finfo.Aliases {
+			_, isKey := fieldsMap[alias]
+			_, isAlias := aliasMap[alias]
+			if isKey || isAlias {
+				return nil, errors.New("duplicated key '" + alias + "' in struct " + st.String())
+			}
+			aliasMap[alias] = finfo.Id
+			addFold(alias, finfo.Id)
+		}
+	}
+
 	sinfo = &structInfo{
 		FieldsMap:          fieldsMap,
 		FieldsList:         fieldsList,
 		InlineMap:          inlineMap,
 		InlineConstructors: inlineConstructors,
+		PresenceFields:     presenceFields,
+		AliasMap:           aliasMap,
+		FoldMap:            foldMap,
 	}
 
 	fieldMapMutex.Lock()
-	structMap[st] = sinfo
+	structMap[key] = sinfo
 	fieldMapMutex.Unlock()
 	return sinfo, nil
 }
+
+// parseDefault parses the value of a ,default= tag option as a YAML
+// document and returns its content node. An empty value is null.
+func parseDefault(value string) (*Node, error) {
+	var doc Node
+	if err := Load([]byte(value), &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return &Node{Kind: ScalarNode, Tag: nullTag}, nil
+	}
+	return doc.Content[0], nil
+}
+
+// jsonTag returns the yaml tag equivalent to the json tag of a field without
+// a yaml tag, whether the json tag has the ,string option, and whether the
+// field is skipped. As in encoding/json, a json tag of "-" skips the field
+// while "-," names its key "-", and embedded structs without a json key are
+// inlined. Options with no yaml equivalent are ignored.
+func jsonTag(field reflect.StructField) (tag string, str, skip bool) {
+	jtag, found := field.Tag.Lookup("json")
+	if jtag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(jtag, ",")
+	tag = parts[0]
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "omitempty", "omitzero":
+			tag += "," + opt
+		case "string":
+			str = true
+		}
+	}
+	if field.Anonymous && parts[0] == "" {
+		ftype := field.Type
+		if ftype.Kind() == reflect.Pointer {
+			ftype = ftype.Elem()
+		}
+		if ftype.Kind() == reflect.Struct {
+			tag += ",inline"
+		}
+	}
+	if !found && tag == "" {
+		return "", false, false
+	}
+	return tag, str, false
+}
+
+// stringKind reports whether the ,string option of a json tag applies to
+// values of type t.
+func stringKind(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -1,13 +1,16 @@
 // Copyright 2025 The go-yaml Project Contributors
 // SPDX-License-Identifier: Apache-2.0
 
-// Tests for isYAMLNodePkg and the Node type allowlist.
+// Tests for isYAMLNodePkg, the Node type allowlist and struct tag options.
 
 package libyaml
 
 import (
+	"errors"
 	"reflect"
 	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
 )
 
 func TestIsYAMLNodePkg(t *testing.T) {
@@ -100,3 +103,236 @@ func TestRealNodeUnmarshal(t *testing.T) {
 		t.Errorf("Value = %q, want %q", y.Value, "hello")
 	}
 }
+
+func TestRequiredAndDefaultFields(t *testing.T) {
+	type Inner struct {
+		Port int `yaml:"port,default=8080"`
+	}
+	type Config struct {
+		Name  string            `yaml:"name,required"`
+		Mode  string            `yaml:"mode,default=fast"`
+		Tags  []string          `yaml:"tags,default=[a, b]"`
+		Label map[string]string `yaml:"label,default={k: v}"`
+		Inner `yaml:",inline"`
+	}
+
+	var cfg Config
+	assert.NoError(t, Load([]byte("name: x\nmode: slow\n"), &cfg))
+	assert.DeepEqual(t, Config{
+		Name:  "x",
+		Mode:  "slow",
+		Tags:  []string{"a", "b"},
+		Label: map[string]string{"k": "v"},
+		Inner: Inner{Port: 8080},
+	}, cfg)
+
+	// Keys merged with << count as present.
+	cfg = Config{}
+	assert.NoError(t, Load([]byte("base: &b {name: x, port: 1}\ncfg:\n  <<: *b\n  tags: []\n"), &struct{ Cfg *Config }{&cfg}))
+	assert.Equal(t, "x", cfg.Name)
+	assert.Equal(t, 1, cfg.Port)
+	assert.Equal(t, 0, len(cfg.Tags))
+
+	// Defaults leave values that are already set alone.
+	cfg = Config{Mode: "preset"}
+	assert.NoError(t, Load([]byte("name: x\n"), &cfg))
+	assert.Equal(t, "preset", cfg.Mode)
+
+	var list []Config
+	err := Load([]byte("- name: a\n- mode: b\n"), &list)
+	assert.ErrorMatches(t, `yaml: construct errors: line 2: required field name not found in type libyaml.Config`, err)
+	var loadErr *LoadError
+	assert.True(t, errors.As(err, &loadErr))
+	assert.Equal(t, Mark{Line: 2, Column: 3}, loadErr.Mark)
+	assert.Equal(t, 2, len(list))
+	assert.Equal(t, "b", list[1].Mode)
+
+	var bad struct {
+		N int `yaml:"n,default=many"`
+	}
+	assert.ErrorMatches(t, "yaml: construct errors: line 1: cannot construct !!str `many` into int", Load([]byte("{}"), &bad))
+}
+
+func TestRequiredAndDefaultTagErrors(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{{
+		value: struct {
+			A int `yaml:"a,required,default=1"`
+		}{},
+		want: `flags required and default both set in tag "a,required,default=1" of type struct .*`,
+	}, {
+		value: struct {
+			A int `yaml:"a,default=[1"`
+		}{},
+		want: `invalid default in tag "a,default=\[1" of type struct .*`,
+	}, {
+		value: struct {
+			A struct{ B int } `yaml:",inline,required"`
+		}{},
+		want: `flags required, default and alias cannot be used with inline in tag ",inline,required" of type struct .*`,
+	}}
+	for _, tc := range tests {
+		_, err := getStructInfo(reflect.TypeOf(tc.value), structOptions{})
+		assert.ErrorMatches(t, tc.want, err)
+	}
+}
+
+func TestFieldNaming(t *testing.T) {
+	tests := []struct {
+		name                               string
+		lower, snake, kebab, camel, pascal string
+	}{
+		{"MaxRetries", "maxretries", "max_retries", "max-retries", "maxRetries", "MaxRetries"},
+		{"HTTPServerID", "httpserverid", "http_server_id", "http-server-id", "httpServerID", "HTTPServerID"},
+		{"ID", "id", "id", "id", "id", "ID"},
+		{"Port2Name", "port2name", "port2_name", "port2-name", "port2Name", "Port2Name"},
+		{"Already_Split", "already_split", "already_split", "already-split", "alreadySplit", "Already_Split"},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.lower, LowerCase.fieldKey(tc.name))
+		assert.Equal(t, tc.snake, SnakeCase.fieldKey(tc.name))
+		assert.Equal(t, tc.kebab, KebabCase.fieldKey(tc.name))
+		assert.Equal(t, tc.camel, CamelCase.fieldKey(tc.name))
+		assert.Equal(t, tc.pascal, PascalCase.fieldKey(tc.name))
+	}
+}
+
+func TestWithFieldNaming(t *testing.T) {
+	type Server struct {
+		MaxRetries int
+		HostName   string `yaml:"host"`
+	}
+	type Config struct {
+		ServerList []Server
+	}
+
+	cfg := Config{ServerList: []Server{{MaxRetries: 3, HostName: "a"}}}
+	out, err := Dump(&cfg, WithFieldNaming(KebabCase))
+	assert.NoError(t, err)
+	assert.Equal(t, "server-list:\n- max-retries: 3\n  host: a\n", string(out))
+
+	var got Config
+	assert.NoError(t, Load(out, &got, WithFieldNaming(KebabCase)))
+	assert.DeepEqual(t, cfg, got)
+
+	// The same type keeps its default naming without the option.
+	out, err = Dump(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "serverlist:\n- maxretries: 3\n  host: a\n", string(out))
+
+	_, err = ApplyOptions(WithFieldNaming(FieldNaming(99)))
+	assert.ErrorMatches(t, "invalid FieldNaming value: 99", err)
+}
+
+func TestAliasAndCaseInsensitiveKeys(t *testing.T) {
+	type Config struct {
+		Timeout int    `yaml:"timeout,alias=time_out,alias=timeOut"`
+		Name    string `yaml:"name"`
+		URL     string
+		Url2    string `yaml:"URL2"`
+	}
+
+	type deprecation struct {
+		alias, key string
+		mark       Mark
+	}
+	var reported []deprecation
+	report := WithDeprecatedKeyFunc(func(alias, key string, mark Mark) {
+		reported = append(reported, deprecation{alias, key, mark})
+	})
+
+	var cfg Config
+	assert.NoError(t, Load([]byte("name: a\ntime_out: 5\n"), &cfg, report, WithKnownFields()))
+	assert.Equal(t, 5, cfg.Timeout)
+	assert.DeepEqual(t, []deprecation{{"time_out", "timeout", Mark{Line: 2, Column: 1}}}, reported)
+
+	// Keys are case-sensitive by default.
+	cfg = Config{}
+	err := Load([]byte("Name: a\n"), &cfg, WithKnownFields())
+	assert.ErrorMatches(t, "yaml: construct errors: line 1: field Name not found in type libyaml.Config", err)
+
+	reported = nil
+	cfg = Config{}
+	assert.NoError(t, Load([]byte("NAME: a\nTime_Out: 5\nUrl: b\nurl2: c\n"), &cfg,
+		report, WithKnownFields(), WithCaseInsensitiveKeys()))
+	assert.DeepEqual(t, Config{Timeout: 5, Name: "a", URL: "b", Url2: "c"}, cfg)
+	assert.DeepEqual(t, []deprecation{{"Time_Out", "timeout", Mark{Line: 2, Column: 1}}}, reported)
+
+	// The key and its alias set the same field.
+	err = Load([]byte("timeout: 1\ntime_out: 2\n"), &cfg)
+	assert.ErrorMatches(t, "yaml: construct errors: line 2: field time_out already set in type libyaml.Config", err)
+
+	// Keys of the mapping holding << win over merged aliases.
+	cfg = Config{}
+	assert.NoError(t, Load([]byte("base: &b {time_out: 1, name: b}\ncfg:\n  <<: *b\n  timeout: 2\n"), &struct{ Cfg *Config }{&cfg}))
+	assert.Equal(t, 2, cfg.Timeout)
+	assert.Equal(t, "b", cfg.Name)
+
+	// A field whose key only differs in case from another must match exactly.
+	type Ambiguous struct {
+		A int `yaml:"key"`
+		B int `yaml:"Key"`
+	}
+	var amb Ambiguous
+	err = Load([]byte("KEY: 1\nKey: 2\n"), &amb, WithKnownFields(), WithCaseInsensitiveKeys())
+	assert.ErrorMatches(t, "yaml: construct errors: line 1: field KEY not found in type libyaml.Ambiguous", err)
+	assert.Equal(t, 2, amb.B)
+
+	var dup struct {
+		A int `yaml:"a"`
+		B int `yaml:"b,alias=a"`
+	}
+	_, err = getStructInfo(reflect.TypeOf(dup), structOptions{})
+	assert.ErrorMatches(t, "duplicated key 'a' in struct .*", err)
+}
+
+func TestWithJSONTags(t *testing.T) {
+	type Meta struct {
+		Labels map[string]string `json:"labels,omitempty"`
+	}
+	type Spec struct {
+		Meta
+		Replicas int    `json:"replicas,string"`
+		Paused   *bool  `json:"paused,string,omitempty"`
+		Image    string `json:"image" yaml:"img"`
+		Secret   string `json:"-"`
+		Note     string `json:",omitzero"`
+	}
+
+	spec := Spec{Meta: Meta{Labels: map[string]string{"app": "web"}}, Replicas: 3, Image: "nginx", Secret: "x"}
+	out, err := Dump(&spec, WithJSONTags())
+	assert.NoError(t, err)
+	assert.Equal(t, "labels:\n  app: web\nreplicas: '3'\nimg: nginx\n", string(out))
+
+	var got Spec
+	assert.NoError(t, Load([]byte("labels: {app: web}\nreplicas: '3'\nimg: nginx\nsecret: x\npaused: 'true'\n"), &got, WithJSONTags()))
+	paused := true
+	assert.DeepEqual(t, Spec{Meta: Meta{Labels: map[string]string{"app": "web"}}, Replicas: 3, Image: "nginx", Paused: &paused}, got)
+
+	// Unquoted values load too.
+	got = Spec{}
+	assert.NoError(t, Load([]byte("replicas: 4\n"), &got, WithJSONTags()))
+	assert.Equal(t, 4, got.Replicas)
+
+	err = Load([]byte("replicas: many\n"), &got, WithJSONTags())
+	assert.ErrorMatches(t, "yaml: construct errors: line 1: cannot construct !!str `many` into int", err)
+
+	// As in encoding/json, "-," names the key "-".
+	type Dash struct {
+		Dash string `json:"-,"`
+		Skip string `json:"-"`
+	}
+	out, err = Dump(&Dash{Dash: "a", Skip: "x"}, WithJSONTags())
+	assert.NoError(t, err)
+	assert.Equal(t, "'-': a\n", string(out))
+	var dash Dash
+	assert.NoError(t, Load([]byte("'-': b\n"), &dash, WithJSONTags()))
+	assert.Equal(t, "b", dash.Dash)
+
+	// Without the option json tags are ignored.
+	out, err = Dump(&spec)
+	assert.NoError(t, err)
+	assert.Equal(t, "meta:\n  labels:\n    app: web\nreplicas: 3\npaused: null\nimg: nginx\nsecret: x\nnote: ''\n", string(out))
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Application tag hooks.
+//
+// A tag plugin constructs and represents values of application tags such as
+// !duration or !semver. The Constructor offers it every node whose tag is
+// not one of the standard tags it constructs itself, and the Representer
+// offers it every value before representing it as usual. Tags produced by
+// the plugin are not implicit, so the Desolver keeps them on output and the
+// values round-trip.
+
+package libyaml
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// TagConstructFunc constructs a node with an application tag into out. It
+// reports whether it handled the node; nodes it doesn't handle are
+// constructed as usual.
+type TagConstructFunc func(ctx *TagContext, n *Node, out reflect.Value) (bool, error)
+
+// TagRepresentFunc represents a value as a node with an application tag. It
+// returns nil to let the value be represented as usual. The value is never
+// an interface; the value it holds is passed instead.
+type TagRepresentFunc func(ctx *TagContext, in reflect.Value) (*Node, error)
+
+// ChainTagConstructFuncs returns a TagConstructFunc that offers nodes to
+// first, then to then if first did not handle them.
+func ChainTagConstructFuncs(first, then TagConstructFunc) TagConstructFunc {
+	if first == nil {
+		return then
+	}
+	return func(ctx *TagContext, n *Node, out reflect.Value) (bool, error) {
+		if ok, err := first(ctx, n, out); ok || err != nil {
+			return ok, err
+		}
+		return then(ctx, n, out)
+	}
+}
+
+// ChainTagRepresentFuncs returns a TagRepresentFunc that offers values to
+// first, then to then if first did not represent them.
+func ChainTagRepresentFuncs(first, then TagRepresentFunc) TagRepresentFunc {
+	if first == nil {
+		return then
+	}
+	return func(ctx *TagContext, in reflect.Value) (*Node, error) {
+		if n, err := first(ctx, in); n != nil || err != nil {
+			return n, err
+		}
+		return then(ctx, in)
+	}
+}
+
+// TagContext gives tag plugins access to the Load or Dump call using them,
+// so that the contents of tagged collections are constructed and
+// represented with the same options.
+type TagContext struct {
+	constructor *Constructor
+	representer *Representer
+}
+
+// Load constructs n into the value pointed to by v.
+func (ctx *TagContext) Load(n *Node, v any) (err error) {
+	c := ctx.constructor
+	if c == nil {
+		return errors.New("yaml: TagContext.Load called while dumping")
+	}
+	out := reflect.ValueOf(v)
+	if out.Kind() != reflect.Pointer || out.IsNil() {
+		return fmt.Errorf("yaml: TagContext.Load needs a non-nil pointer, got %T", v)
+	}
+	defer handleErr(&err)
+	terrlen := len(c.TypeErrors)
+	c.Construct(n, out.Elem())
+	if len(c.TypeErrors) > terrlen {
+		issues := c.TypeErrors[terrlen:]
+		c.TypeErrors = c.TypeErrors[:terrlen]
+		return &LoadErrors{issues}
+	}
+	return nil
+}
+
+// Dump represents v as a node.
+func (ctx *TagContext) Dump(v any) (n *Node, err error) {
+	r := ctx.representer
+	if r == nil {
+		return nil, errors.New("yaml: TagContext.Dump called while loading")
+	}
+	defer handleErr(&err)
+	return r.represent("", reflect.ValueOf(v)), nil
+}
+
+// isCoreTag reports whether tag is one of the standard tags the
+// Constructor handles itself.
+func isCoreTag(tag string) bool {
+	switch shortTag(tag) {
+	case "", nullTag, boolTag, strTag, intTag, floatTag, timestampTag, seqTag, mapTag, binaryTag, mergeTag:
+		return true
+	}
+	return false
+}
+
+// constructTag offers n to the tag plugin, and reports whether the plugin
+// handled it.
+func (c *Constructor) constructTag(n *Node, out reflect.Value) (handled, good bool) {
+	handled, err := c.tagConstruct(&TagContext{constructor: c}, n, out)
+	switch e := err.(type) {
+	case nil:
+		return handled, true
+	case *LoadErrors:
+		c.TypeErrors = append(c.TypeErrors, e.Errors...)
+	default:
+		c.TypeErrors = append(c.TypeErrors, formatConstructorError(
+			err,
+			Mark{Line: n.Line, Column: n.Column},
+		))
+	}
+	return true, false
+}
+
+// representTag offers in to the tag plugin, and returns the node it
+// produced, if any. Interfaces are offered the value they hold.
+func (r *Representer) representTag(in reflect.Value) *Node {
+	for in.Kind() == reflect.Interface {
+		if in.IsNil() {
+			return nil
+		}
+		in = in.Elem()
+	}
+	node, err := r.tagRepresent(&TagContext{representer: r}, in)
+	if err != nil {
+		failDump(RepresenterStage, err)
+	}
+	return node
+}
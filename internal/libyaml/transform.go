@@ -0,0 +1,129 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Node transform hooks.
+//
+// A node plugin rewrites each document node between the Resolver and the
+// Constructor when loading, and between the Representer and the Desolver
+// when dumping. Loading resolves the nodes it adds without a tag, and
+// dumping removes inferable tags from the nodes it adds, so plugins can
+// work with plain values either way.
+
+package libyaml
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NodeFunc transforms a document node. It returns the document node to go
+// on with, or nil to go on with doc after changing it in place.
+type NodeFunc func(ctx *NodeContext, doc *Node) (*Node, error)
+
+// NodeContext tells node plugins which side of a Load or Dump call the
+// document is on.
+type NodeContext struct {
+	// Stage is ResolverStage for documents being loaded, with their tags
+	// resolved, and RepresenterStage for documents being dumped, before
+	// inferable tags are removed.
+	Stage Stage
+
+	loader *Loader
+}
+
+// LoadIncluded composes and resolves the documents of data, the content of
+// the file name included by the document being loaded. It uses the options
+// of the running Load call, and counts the bytes of data and the documents,
+// nodes and anchors in it against the resource limits of the call, as part
+// of the document being loaded. The nodes keep their positions in the
+// included file, and errors name the file.
+func (ctx *NodeContext) LoadIncluded(name string, data []byte) (docs []*Node, err error) {
+	l := ctx.loader
+	if l == nil {
+		return nil, errors.New("yaml: NodeContext.LoadIncluded called while dumping")
+	}
+	defer func() {
+		if err != nil {
+			err = inFile(name, err)
+		}
+	}()
+	defer handleErr(&err)
+	docs = l.composer.include(data)
+	for _, doc := range docs {
+		l.resolver.Resolve(doc)
+	}
+	return docs, nil
+}
+
+// inFile returns err as a LoadError whose message names the file it
+// happened in.
+func inFile(name string, err error) error {
+	var lerr *LoadError
+	if !errors.As(err, &lerr) {
+		return NewLoadError(ComposerStage, fmt.Sprintf("%s: %v", name, err), Mark{}, err)
+	}
+	named := *lerr
+	named.Message = fmt.Sprintf("%s: %s", name, lerr.Message)
+	named.err = lerr
+	return &named
+}
+
+// ChainNodeFuncs returns a NodeFunc that transforms documents with first,
+// then with then.
+func ChainNodeFuncs(first, then NodeFunc) NodeFunc {
+	if first == nil {
+		return then
+	}
+	return func(ctx *NodeContext, doc *Node) (*Node, error) {
+		out, err := first(ctx, doc)
+		if err != nil {
+			return nil, err
+		}
+		if out == nil {
+			out = doc
+		}
+		next, err := then(ctx, out)
+		if next == nil && err == nil {
+			next = out
+		}
+		return next, err
+	}
+}
+
+// transformLoaded passes the loaded document doc through the node hook and
+// resolves the nodes the hook added.
+func (l *Loader) transformLoaded(doc *Node) *Node {
+	if l.options.NodeHook == nil {
+		return doc
+	}
+	out, err := l.options.NodeHook(&NodeContext{Stage: ResolverStage, loader: l}, doc)
+	if err != nil {
+		switch err.(type) {
+		case *LoadError, *LoadErrors:
+			Fail(err)
+		}
+		Fail(NewLoadError(ResolverStage, err.Error(),
+			Mark{Line: doc.Line, Column: doc.Column}, err))
+	}
+	if out == nil {
+		out = doc
+	}
+	l.resolver.Resolve(out)
+	return out
+}
+
+// transformDumped passes the represented document doc through the node
+// hook of opts.
+func transformDumped(opts *Options, doc *Node) *Node {
+	if opts.NodeHook == nil {
+		return doc
+	}
+	out, err := opts.NodeHook(&NodeContext{Stage: RepresenterStage}, doc)
+	if err != nil {
+		failDump(RepresenterStage, err)
+	}
+	if out == nil {
+		out = doc
+	}
+	return out
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for node transform hooks.
+
+package libyaml
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+// withNodeHook sets the node hook, as WithPlugin does for a node plugin.
+func withNodeHook(fn NodeFunc) Option {
+	return func(o *Options) error {
+		o.NodeHook = fn
+		return nil
+	}
+}
+
+// expandEnv expands ${NAME} in string scalars and strips x- keys.
+func expandEnv(ctx *NodeContext, doc *Node) (*Node, error) {
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		switch {
+		case n.Kind == ScalarNode && n.Tag == strTag:
+			n.Value = os.Expand(n.Value, func(name string) string {
+				return map[string]string{"HOST": "db", "PORT": "5432"}[name]
+			})
+		case n.Kind == MappingNode:
+			content := n.Content[:0]
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if !strings.HasPrefix(n.Content[i].Value, "x-") {
+					content = append(content, n.Content[i], n.Content[i+1])
+				}
+			}
+			n.Content = content
+		}
+		for _, c := range n.Content {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return nil, nil
+}
+
+func TestNodeHookLoad(t *testing.T) {
+	var v map[string]any
+	err := Load([]byte("url: '${HOST}:${PORT}'\nport: ${PORT}\nx-note: skip\n"), &v, withNodeHook(expandEnv))
+	assert.NoError(t, err)
+	// Tags are resolved before the hook runs, so the plain ${PORT} stays a
+	// string.
+	assert.DeepEqual(t, map[string]any{"url": "db:5432", "port": "5432"}, v)
+
+	// Nodes added without a tag are resolved.
+	addDefaults := func(ctx *NodeContext, doc *Node) (*Node, error) {
+		assert.Equal(t, ResolverStage, ctx.Stage)
+		m := doc.Content[0]
+		m.Content = append(m.Content,
+			&Node{Kind: ScalarNode, Value: "retries"},
+			&Node{Kind: ScalarNode, Value: "3"})
+		return doc, nil
+	}
+	v = nil
+	assert.NoError(t, Load([]byte("a: 1\n"), &v, withNodeHook(addDefaults)))
+	assert.DeepEqual(t, map[string]any{"a": 1, "retries": 3}, v)
+
+	// Documents can be replaced.
+	replace := func(ctx *NodeContext, doc *Node) (*Node, error) {
+		return &Node{Kind: DocumentNode, Content: []*Node{{Kind: ScalarNode, Value: "replaced"}}}, nil
+	}
+	var n Node
+	assert.NoError(t, Load([]byte("a: 1\n"), &n, withNodeHook(replace)))
+	assert.Equal(t, "replaced", n.Content[0].Value)
+	assert.Equal(t, strTag, n.Content[0].Tag)
+}
+
+func TestNodeHookDump(t *testing.T) {
+	v := map[string]any{"url": "${HOST}", "x-note": "skip", "port": 80}
+	out, err := Dump(v, withNodeHook(expandEnv))
+	assert.NoError(t, err)
+	assert.Equal(t, "port: 80\nurl: db\n", string(out))
+
+	// Nodes added without a tag are written as plain values.
+	addHeader := func(ctx *NodeContext, doc *Node) (*Node, error) {
+		assert.Equal(t, RepresenterStage, ctx.Stage)
+		m := doc.Content[0]
+		m.Content = append([]*Node{
+			{Kind: ScalarNode, Value: "version"},
+			{Kind: ScalarNode, Value: "2"},
+		}, m.Content...)
+		return nil, nil
+	}
+	out, err = Dump(map[string]int{"a": 1}, withNodeHook(addHeader))
+	assert.NoError(t, err)
+	assert.Equal(t, "version: 2\na: 1\n", string(out))
+
+	var n Node
+	assert.NoError(t, n.Dump(map[string]int{"a": 1}, withNodeHook(addHeader)))
+	assert.Equal(t, "version", n.Content[0].Value)
+}
+
+func TestNodeHookErrors(t *testing.T) {
+	errDenied := errors.New("denied")
+	deny := func(ctx *NodeContext, doc *Node) (*Node, error) {
+		return nil, errDenied
+	}
+	var v any
+	err := Load([]byte("\n\na: 1\n"), &v, withNodeHook(deny))
+	var lerr *LoadError
+	assert.True(t, errors.As(err, &lerr))
+	assert.Equal(t, "go-yaml load error in resolver at L3.C1: denied", lerr.Error())
+	assert.True(t, errors.Is(err, errDenied))
+
+	// Load errors are returned as they are.
+	positioned := func(ctx *NodeContext, doc *Node) (*Node, error) {
+		k := doc.Content[0].Content[0]
+		return nil, NewLoadError(ConstructorStage, "key not allowed", Mark{Line: k.Line, Column: k.Column}, nil)
+	}
+	err = Load([]byte("a: 1\n"), &v, withNodeHook(positioned))
+	assert.Equal(t, "go-yaml load error in constructor at L1.C1: key not allowed", err.Error())
+
+	_, err = Dump(1, withNodeHook(deny))
+	var derr *DumpError
+	assert.True(t, errors.As(err, &derr))
+	assert.True(t, errors.Is(err, errDenied))
+}
+
+func TestChainNodeFuncs(t *testing.T) {
+	// The second function sees the document the first one returns.
+	chained := ChainNodeFuncs(
+		func(ctx *NodeContext, doc *Node) (*Node, error) {
+			return &Node{Kind: DocumentNode, Content: []*Node{{Kind: ScalarNode, Value: "first"}}}, nil
+		},
+		func(ctx *NodeContext, doc *Node) (*Node, error) {
+			doc.Content[0].Value += ",second"
+			return nil, nil
+		})
+	var v any
+	assert.NoError(t, Load([]byte("x\n"), &v, withNodeHook(chained)))
+	assert.Equal(t, "first,second", v)
+}
+
+func TestNodeContextLoadIncluded(t *testing.T) {
+	include := func(ctx *NodeContext, doc *Node) (*Node, error) {
+		n := doc.Content[0]
+		docs, err := ctx.LoadIncluded(n.Value, []byte("a: 1\n---\nb: [2, 3]\n"))
+		if err != nil {
+			return nil, err
+		}
+		doc.Content[0] = &Node{Kind: SequenceNode, Content: []*Node{docs[0].Content[0], docs[1].Content[0]}}
+		return nil, nil
+	}
+	var v any
+	assert.NoError(t, Load([]byte("inc.yaml\n"), &v, withNodeHook(include)))
+	assert.DeepEqual(t, []any{map[string]any{"a": 1}, map[string]any{"b": []any{2, 3}}}, v)
+
+	// Included documents count against the limits of the Load call.
+	limited := func(o *Options) error {
+		o.ResourceLimits = ResourceLimits{Nodes: 8}
+		return nil
+	}
+	err := Load([]byte("inc.yaml\n"), &v, withNodeHook(include), limited)
+	assert.Equal(t, "go-yaml load error in composer at L2.C1: inc.yaml: exceeded max node count of 8", err.Error())
+
+	var ctx NodeContext
+	_, err = ctx.LoadIncluded("inc.yaml", nil)
+	assert.ErrorMatches(t, "yaml: NodeContext.LoadIncluded called while dumping", err)
+}
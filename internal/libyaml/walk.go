@@ -0,0 +1,239 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Depth-first traversal of Node trees.
+//
+// Walk calls a Visitor when entering and leaving each node, with a Cursor
+// describing where the node sits: its parent, the key it is stored under,
+// its index and its path. Visitors steer the walk through the WalkAction
+// they return and may replace the current node through the Cursor.
+
+package libyaml
+
+import "fmt"
+
+// WalkAction tells [Walk] how to proceed after a visitor call.
+type WalkAction int
+
+const (
+	// WalkContinue proceeds with the walk.
+	WalkContinue WalkAction = iota
+
+	// WalkSkip, returned from Enter, skips the children of the current
+	// node. Its Leave call is still made. From Leave it is the same as
+	// WalkContinue.
+	WalkSkip
+
+	// WalkStop ends the walk immediately. No further Enter or Leave calls
+	// are made.
+	WalkStop
+)
+
+// Visitor receives the nodes visited by [Walk].
+type Visitor interface {
+	// Enter is called before the children of a node are visited.
+	Enter(c *Cursor) WalkAction
+
+	// Leave is called after the children of a node were visited.
+	Leave(c *Cursor) WalkAction
+}
+
+// VisitorFunc adapts a function to the [Visitor] interface. The function is
+// called on Enter; Leave does nothing.
+type VisitorFunc func(c *Cursor) WalkAction
+
+// Enter calls f(c).
+func (f VisitorFunc) Enter(c *Cursor) WalkAction {
+	return f(c)
+}
+
+// Leave returns WalkContinue.
+func (f VisitorFunc) Leave(c *Cursor) WalkAction {
+	return WalkContinue
+}
+
+// Cursor describes the position of the node being visited.
+// A Cursor is only valid during the visitor call it is passed to.
+type Cursor struct {
+	// Node is the node being visited. When aliases are followed, it is the
+	// alias target and Alias holds the alias node itself.
+	Node  *Node
+	Alias *Node
+
+	// Parent is the collection or document holding the node, or nil for
+	// the node Walk started from.
+	Parent *Node
+
+	// Key is the key node when Node is a mapping value, and nil otherwise.
+	Key *Node
+
+	// IsKey reports whether Node is a mapping key; keys are visited only
+	// with [WalkKeys].
+	IsKey bool
+
+	// Index is the position of the node in Parent.Content, or -1 for the
+	// node Walk started from.
+	Index int
+
+	// Path locates the node as a path expression accepted by
+	// [CompilePath]. Mapping keys have the path of their value.
+	Path string
+
+	// Depth is the number of collections between the node and the node
+	// Walk started from. Documents do not count.
+	Depth int
+
+	replace func(n *Node) *Node // Installs n and returns the node now in place
+}
+
+// Replace puts n in place of the current node in its parent. Children of
+// n are visited next unless Enter returns WalkSkip. When the node Walk
+// started from is replaced, n is copied into it. When the current node was
+// reached through an alias, the alias itself is replaced.
+func (c *Cursor) Replace(n *Node) {
+	c.Node = c.replace(n)
+	c.Alias = nil
+}
+
+// WalkOption configures [Walk].
+type WalkOption func(*walkOptions)
+
+type walkOptions struct {
+	followAliases bool
+	keys          bool
+}
+
+// WalkFollowAliases makes [Walk] visit the target of each alias in place of
+// the alias node. An alias that refers to one of its own ancestors makes
+// Walk fail rather than loop. Like decoding, Walk also fails when far more
+// nodes are visited through aliases than outside them, so that documents
+// nesting aliases to aliases cannot make it take exponential time.
+func WalkFollowAliases() WalkOption {
+	return func(o *walkOptions) {
+		o.followAliases = true
+	}
+}
+
+// WalkKeys makes [Walk] visit mapping keys as well as values. Key nodes are
+// visited before their value, with Cursor.IsKey set.
+func WalkKeys() WalkOption {
+	return func(o *walkOptions) {
+		o.keys = true
+	}
+}
+
+// Walk traverses the tree rooted at n depth-first in document order,
+// calling v.Enter before and v.Leave after the children of each node.
+// Mapping values are visited with the key they are stored under.
+//
+// Walk returns an error only when following aliases, either because an
+// alias refers to one of its own ancestors or because DefaultAliasCheck
+// finds excessive aliasing.
+func Walk(n *Node, v Visitor, opts ...WalkOption) error {
+	if n == nil {
+		return nil
+	}
+	w := &walker{v: v, visiting: make(map[*Node]bool)}
+	for _, opt := range opts {
+		opt(&w.opts)
+	}
+	c := &Cursor{Node: n, Index: -1, Path: "$"}
+	c.replace = func(r *Node) *Node {
+		*n = *r
+		return n
+	}
+	w.walk(c, n)
+	return w.err
+}
+
+// walker holds the state of a single Walk call.
+type walker struct {
+	v        Visitor
+	opts     walkOptions
+	visiting map[*Node]bool // Nodes on the current path, for cycle detection
+	err      error
+
+	// Nodes visited, and nodes visited through a followed alias, checked
+	// with DefaultAliasCheck.
+	visitCount int
+	aliasCount int
+	aliasDepth int
+}
+
+// walk visits the node at the cursor and its children. It reports whether
+// the walk must stop.
+func (w *walker) walk(c *Cursor, n *Node) bool {
+	if w.opts.followAliases && n.Kind == AliasNode && n.Alias != nil {
+		if w.visiting[n.Alias] {
+			w.err = fmt.Errorf("yaml: alias *%s at line %d, column %d refers to its own ancestor", n.Value, n.Line, n.Column)
+			return true
+		}
+		c.Alias = n
+		n = n.Alias
+		w.aliasDepth++
+		defer func() { w.aliasDepth-- }()
+	}
+	w.visitCount++
+	if w.aliasDepth > 0 {
+		w.aliasCount++
+		if err := DefaultAliasCheck(w.aliasCount, w.visitCount); err != nil {
+			w.err = fmt.Errorf("yaml: %w", err)
+			return true
+		}
+	}
+	c.Node = n
+	action := w.v.Enter(c)
+	if action == WalkStop {
+		return true
+	}
+	n = c.Node
+	if action != WalkSkip && n != nil {
+		if w.children(c, n) {
+			return true
+		}
+	}
+	return w.v.Leave(c) == WalkStop
+}
+
+// children visits the children of n, positioned at c.
+func (w *walker) children(c *Cursor, n *Node) bool {
+	w.visiting[n] = true
+	defer delete(w.visiting, n)
+	depth := c.Depth
+	if n.Kind == MappingNode || n.Kind == SequenceNode {
+		depth++
+	}
+	child := func(i int, path string, key *Node, isKey bool) bool {
+		cc := &Cursor{Parent: n, Key: key, IsKey: isKey, Index: i, Path: path, Depth: depth}
+		cc.replace = func(r *Node) *Node {
+			n.Content[i] = r
+			return r
+		}
+		return w.walk(cc, n.Content[i])
+	}
+	switch n.Kind {
+	case DocumentNode, StreamNode:
+		for i := range n.Content {
+			if child(i, c.Path, nil, false) {
+				return true
+			}
+		}
+	case SequenceNode:
+		for i := range n.Content {
+			if child(i, appendPathIndex(c.Path, i), nil, false) {
+				return true
+			}
+		}
+	case MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			path := appendPathKey(c.Path, n.Content[i])
+			if w.opts.keys && child(i, path, nil, true) {
+				return true
+			}
+			if child(i+1, path, n.Content[i], false) {
+				return true
+			}
+		}
+	}
+	return false
+}
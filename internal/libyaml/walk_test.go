@@ -0,0 +1,198 @@
+// Copyright 2025 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Tests for depth-first traversal of Node trees.
+
+package libyaml
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+)
+
+// recorder is a Visitor that records its calls.
+type recorder struct {
+	calls []string
+	enter func(c *Cursor) WalkAction
+}
+
+func (r *recorder) label(c *Cursor) string {
+	s := fmt.Sprintf("%s d%d", c.Path, c.Depth)
+	switch c.Node.Kind {
+	case ScalarNode:
+		s += "=" + c.Node.Value
+	case AliasNode:
+		s += "=*" + c.Node.Value
+	}
+	if c.Key != nil {
+		s += " key=" + c.Key.Value
+	}
+	if c.IsKey {
+		s += " (key)"
+	}
+	if c.Alias != nil {
+		s += " via *" + c.Alias.Value
+	}
+	return s
+}
+
+func (r *recorder) Enter(c *Cursor) WalkAction {
+	r.calls = append(r.calls, "> "+r.label(c))
+	if r.enter != nil {
+		return r.enter(c)
+	}
+	return WalkContinue
+}
+
+func (r *recorder) Leave(c *Cursor) WalkAction {
+	if c.Node.Kind != ScalarNode && c.Node.Kind != AliasNode {
+		r.calls = append(r.calls, "< "+c.Path)
+	}
+	return WalkContinue
+}
+
+func TestWalk(t *testing.T) {
+	var doc Node
+	assert.NoError(t, Load([]byte("a: &x {b: 1}\nl: [2, *x]\n"), &doc))
+
+	r := &recorder{}
+	assert.NoError(t, Walk(&doc, r))
+	assert.DeepEqual(t, []string{
+		"> $ d0",
+		"> $ d0",
+		"> $.a d1 key=a",
+		"> $.a.b d2=1 key=b",
+		"< $.a",
+		"> $.l d1 key=l",
+		"> $.l[0] d2=2",
+		"> $.l[1] d2=*x",
+		"< $.l",
+		"< $",
+		"< $",
+	}, r.calls)
+
+	r = &recorder{}
+	assert.NoError(t, Walk(doc.Content[0], r, WalkFollowAliases(), WalkKeys()))
+	assert.DeepEqual(t, []string{
+		"> $ d0",
+		"> $.a d1=a (key)",
+		"> $.a d1 key=a",
+		"> $.a.b d2=b (key)",
+		"> $.a.b d2=1 key=b",
+		"< $.a",
+		"> $.l d1=l (key)",
+		"> $.l d1 key=l",
+		"> $.l[0] d2=2",
+		"> $.l[1] d2 via *x",
+		"> $.l[1].b d3=b (key)",
+		"> $.l[1].b d3=1 key=b",
+		"< $.l[1]",
+		"< $.l",
+		"< $",
+	}, r.calls)
+}
+
+func TestWalkSkipAndStop(t *testing.T) {
+	var doc Node
+	assert.NoError(t, Load([]byte("skip: {x: 1}\nkeep: {y: 2}\nstop: 3\nafter: 4\n"), &doc))
+
+	var seen []string
+	err := Walk(&doc, VisitorFunc(func(c *Cursor) WalkAction {
+		seen = append(seen, c.Path)
+		switch {
+		case c.Key != nil && c.Key.Value == "skip":
+			return WalkSkip
+		case c.Key != nil && c.Key.Value == "stop":
+			return WalkStop
+		}
+		return WalkContinue
+	}))
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"$", "$", "$.skip", "$.keep", "$.keep.y", "$.stop"}, seen)
+}
+
+func TestWalkReplace(t *testing.T) {
+	var doc Node
+	assert.NoError(t, Load([]byte("name: web # keep\nlist: [a, b]\nref: &r x\nuse: *r\n"), &doc))
+
+	err := Walk(&doc, VisitorFunc(func(c *Cursor) WalkAction {
+		n := c.Node
+		switch {
+		case c.IsKey:
+			c.Replace(&Node{Kind: ScalarNode, Value: strings.ToUpper(n.Value), HeadComment: n.HeadComment})
+		case n.Kind == ScalarNode && n.Value == "web":
+			c.Replace(&Node{Kind: ScalarNode, Value: "api", LineComment: n.LineComment})
+		case n.Kind == SequenceNode:
+			// Children of the replacement are visited next.
+			c.Replace(&Node{Kind: SequenceNode, Content: []*Node{{Kind: ScalarNode, Value: "c"}}})
+		case n.Kind == ScalarNode && n.Value == "c":
+			c.Replace(&Node{Kind: ScalarNode, Value: "d"})
+		case c.Alias != nil:
+			// Replacing a followed alias replaces the alias, not the anchor.
+			c.Replace(&Node{Kind: ScalarNode, Value: "y"})
+		}
+		return WalkContinue
+	}), WalkKeys(), WalkFollowAliases())
+	assert.NoError(t, err)
+	out, err := Dump(&doc)
+	assert.NoError(t, err)
+	assert.Equal(t, "NAME: api # keep\nLIST:\n- d\nREF: &r x\nUSE: y\n", string(out))
+
+	// Replacing the starting node updates it in place.
+	root := &Node{Kind: ScalarNode, Value: "old"}
+	assert.NoError(t, Walk(root, VisitorFunc(func(c *Cursor) WalkAction {
+		c.Replace(&Node{Kind: ScalarNode, Value: "new"})
+		assert.Equal(t, root, c.Node)
+		return WalkContinue
+	})))
+	assert.Equal(t, "new", root.Value)
+}
+
+func TestWalkAliasCycle(t *testing.T) {
+	a := &Node{Kind: SequenceNode, Anchor: "a"}
+	a.Content = []*Node{{Kind: AliasNode, Value: "a", Alias: a, Line: 1, Column: 7}}
+
+	count := 0
+	err := Walk(a, VisitorFunc(func(c *Cursor) WalkAction {
+		count++
+		return WalkContinue
+	}), WalkFollowAliases())
+	assert.ErrorMatches(t, `yaml: alias \*a at line 1, column 7 refers to its own ancestor`, err)
+	assert.Equal(t, 1, count)
+
+	// Without following aliases the alias is a leaf.
+	assert.NoError(t, Walk(a, VisitorFunc(func(c *Cursor) WalkAction { return WalkContinue })))
+}
+
+func TestWalkExcessiveAliasing(t *testing.T) {
+	// Each level holds nine aliases to the level below, so following
+	// aliases would visit over 400 million nodes.
+	src := "a: &a [x, x, x, x, x, x, x, x, x]\n"
+	prev := "a"
+	for _, name := range []string{"b", "c", "d", "e", "f", "g", "h", "i"} {
+		src += name + ": &" + name + " [" + strings.Repeat("*"+prev+", ", 8) + "*" + prev + "]\n"
+		prev = name
+	}
+	var doc Node
+	assert.NoError(t, Load([]byte(src), &doc))
+
+	count := 0
+	err := Walk(&doc, VisitorFunc(func(c *Cursor) WalkAction {
+		count++
+		return WalkContinue
+	}), WalkFollowAliases())
+	assert.ErrorMatches(t, `yaml: document contains excessive aliasing`, err)
+	assert.True(t, count < 10000)
+
+	// Moderate reuse of anchors is fine.
+	assert.NoError(t, Load([]byte("a: &a {x: 1}\nb: *a\nc: *a\n"), &doc))
+	count = 0
+	assert.NoError(t, Walk(&doc, VisitorFunc(func(c *Cursor) WalkAction {
+		count++
+		return WalkContinue
+	}), WalkFollowAliases()))
+	assert.Equal(t, 8, count)
+}
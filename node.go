@@ -96,3 +96,202 @@ const (
 	// FlowStyle uses flow style (inline) formatting.
 	FlowStyle = libyaml.FlowStyle
 )
+
+// -----------------------------------------------------------------------------
+// Path queries
+// -----------------------------------------------------------------------------
+
+// Path is a compiled path expression that selects nodes from a Node tree.
+// Use [Node.Query] for one-off queries and [CompilePath] to reuse a path.
+//
+// The syntax is a JSONPath dialect: "$" is the root, ".key" and "['key']"
+// select mapping values, "*" selects all children, ".." descends
+// recursively, "[0]", "[-1]" and "[1:3]" select sequence items, and
+// "[?(@.kind == 'Pod')]" filters children by a predicate. Evaluation
+// follows aliases and honors "<<" merge keys.
+//
+// For example:
+//
+//	images, err := node.Query("spec.containers[*].image")
+type Path = libyaml.Path
+
+// CompilePath parses a path expression into a [Path].
+var CompilePath = libyaml.CompilePath
+
+// MustCompilePath is like [CompilePath] but panics if the expression cannot
+// be parsed.
+var MustCompilePath = libyaml.MustCompilePath
+
+// ErrNotFound is returned by the Node editing methods (such as
+// [Node.DeleteKey] and [Node.SetPath]) when a key, index or path does not
+// match anything. Test for it with [errors.Is].
+var ErrNotFound = libyaml.ErrNotFound
+
+// -----------------------------------------------------------------------------
+// Structural diff
+// -----------------------------------------------------------------------------
+
+type (
+	// Change describes a single difference reported by [Diff], with its
+	// path and the source positions on both sides.
+	Change = libyaml.Change
+
+	// ChangeKind identifies whether a [Change] is an addition, removal,
+	// modification or reordering.
+	ChangeKind = libyaml.ChangeKind
+
+	// DiffOption configures [Diff].
+	DiffOption = libyaml.DiffOption
+)
+
+// Change kinds reported by [Diff].
+const (
+	ChangeAdded     = libyaml.ChangeAdded
+	ChangeRemoved   = libyaml.ChangeRemoved
+	ChangeModified  = libyaml.ChangeModified
+	ChangeReordered = libyaml.ChangeReordered
+)
+
+// Diff compares two node trees and returns the differences from a to b.
+// It returns an error if a hand-built tree has an alias cycle.
+//
+// Scalars are compared by resolved tag and value, so "0x10" and "16" are
+// equal while "16" and "'16'" are not. Aliases are followed, merge keys are
+// expanded and mapping key order is ignored unless [DiffKeyOrder] is given.
+//
+// For example:
+//
+//	changes, err := yaml.Diff(&before, &after)
+//	if err != nil {
+//	        return err
+//	}
+//	for _, c := range changes {
+//	        fmt.Printf("%s at %s\n", c, c.ToMark)
+//	}
+var Diff = libyaml.Diff
+
+// DiffKeyOrder makes [Diff] report reordered mapping keys as
+// [ChangeReordered].
+var DiffKeyOrder = libyaml.DiffKeyOrder
+
+// UnifiedDiff renders the changes returned by [Diff] in a unified-diff style,
+// labelling the old and new trees with fromName and toName.
+var UnifiedDiff = libyaml.UnifiedDiff
+
+// -----------------------------------------------------------------------------
+// Merging documents
+// -----------------------------------------------------------------------------
+
+type (
+	// MergeStrategy selects how [Merge] combines a base value with an
+	// overlay value.
+	MergeStrategy = libyaml.MergeStrategy
+
+	// MergeOption configures [Merge].
+	MergeOption = libyaml.MergeOption
+)
+
+// Merge strategies for [MergeRule].
+const (
+	MergeDeep    = libyaml.MergeDeep
+	MergeReplace = libyaml.MergeReplace
+	MergeAppend  = libyaml.MergeAppend
+	MergePrepend = libyaml.MergePrepend
+	MergeByKey   = libyaml.MergeByKey
+)
+
+// Merge returns a new tree holding overlay applied on top of base, as used
+// to layer environment overrides over a base configuration.
+//
+// Mappings are merged key by key and other values are replaced, unless a
+// [MergeRule] or [MergeKey] option selects another strategy for a path.
+// An overlay value tagged !reset replaces the base value instead of merging
+// into it, and an empty !reset value deletes the key:
+//
+//	spec:
+//	  env: !reset {MODE: prod}  # drop the base env entirely
+//	  debug: !reset             # remove the key
+//
+// Comments from both inputs are kept, and the result can be passed straight
+// to [Dump] or a [Dumper].
+var Merge = libyaml.Merge
+
+// MergeRule applies a [MergeStrategy] to the values selected by a path
+// expression (see [CompilePath]) in either input of [Merge].
+var MergeRule = libyaml.MergeRule
+
+// MergeKey makes [Merge] match the items of the sequences selected by a path
+// expression on the value of a key field, merging matching items and
+// appending the rest.
+var MergeKey = libyaml.MergeKey
+
+// -----------------------------------------------------------------------------
+// JSON Patch
+// -----------------------------------------------------------------------------
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch document to a node tree in
+// place, addressing locations with RFC 6901 JSON Pointers. Comments and
+// styles of the content the patch does not touch are preserved, and a
+// replaced value keeps the comments of the value it replaces.
+//
+// The patch is applied atomically; on error the tree is unchanged. The
+// patch document may be JSON or YAML.
+var ApplyJSONPatch = libyaml.ApplyJSONPatch
+
+// ApplyMergePatch applies an RFC 7386 JSON Merge Patch document to a node
+// tree in place.
+var ApplyMergePatch = libyaml.ApplyMergePatch
+
+// CreatePatch returns the RFC 6902 JSON Patch document, encoded as JSON,
+// that turns one node tree into another. Values are compared the same way
+// as in [Diff].
+var CreatePatch = libyaml.CreatePatch
+
+// -----------------------------------------------------------------------------
+// Walking node trees
+// -----------------------------------------------------------------------------
+
+type (
+	// WalkAction tells [Walk] how to proceed after a visitor call.
+	WalkAction = libyaml.WalkAction
+
+	// Visitor receives the nodes visited by [Walk].
+	Visitor = libyaml.Visitor
+
+	// VisitorFunc adapts a function to the [Visitor] interface.
+	VisitorFunc = libyaml.VisitorFunc
+
+	// Cursor describes the position of the node being visited by [Walk]:
+	// its parent, key, index and path. It can replace the node.
+	Cursor = libyaml.Cursor
+
+	// WalkOption configures [Walk].
+	WalkOption = libyaml.WalkOption
+)
+
+// Walk actions returned by a [Visitor].
+const (
+	WalkContinue = libyaml.WalkContinue
+	WalkSkip     = libyaml.WalkSkip
+	WalkStop     = libyaml.WalkStop
+)
+
+// Walk traverses a node tree depth-first in document order, calling the
+// visitor when entering and leaving each node. The visitor can skip the
+// children of a node, stop the walk, or replace the current node through
+// the [Cursor]:
+//
+//	yaml.Walk(&doc, yaml.VisitorFunc(func(c *yaml.Cursor) yaml.WalkAction {
+//		if c.Node.Tag == "!secret" {
+//			c.Replace(&yaml.Node{Kind: yaml.ScalarNode, Value: "<redacted>"})
+//		}
+//		return yaml.WalkContinue
+//	}))
+var Walk = libyaml.Walk
+
+// WalkFollowAliases makes [Walk] visit alias targets in place of alias
+// nodes, failing on aliases that refer to their own ancestors.
+var WalkFollowAliases = libyaml.WalkFollowAliases
+
+// WalkKeys makes [Walk] visit mapping keys as well as values.
+var WalkKeys = libyaml.WalkKeys
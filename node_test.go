@@ -9,6 +9,7 @@ package yaml_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -784,3 +785,103 @@ func TestNodeDumpInvalidOptions(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.ErrorMatches(t, ".*indent must be.*", err)
 }
+
+func TestNodeQuery(t *testing.T) {
+	var node yaml.Node
+	err := yaml.Load([]byte("spec:\n  containers:\n  - image: nginx\n  - image: envoy\n"), &node)
+	assert.NoError(t, err)
+
+	path := yaml.MustCompilePath("spec.containers[*].image")
+	nodes, err := path.Find(&node)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(nodes))
+	assert.Equal(t, "nginx", nodes[0].Value)
+	assert.Equal(t, 3, nodes[0].Line)
+	assert.Equal(t, "envoy", nodes[1].Value)
+
+	_, err = node.Query("spec[")
+	assert.ErrorMatches(t, `yaml: invalid path .*`, err)
+}
+
+func TestNodeEdit(t *testing.T) {
+	var node yaml.Node
+	err := yaml.Load([]byte("# config\nname: app # the name\nport: 80\n"), &node)
+	assert.NoError(t, err)
+
+	err = node.SetPath("name", &yaml.Node{Kind: yaml.ScalarNode, Value: "api"})
+	assert.NoError(t, err)
+	err = node.Content[0].DeleteKey("port")
+	assert.NoError(t, err)
+	err = node.Content[0].DeleteKey("port")
+	assert.True(t, errors.Is(err, yaml.ErrNotFound))
+
+	out, err := yaml.Dump(&node)
+	assert.NoError(t, err)
+	assert.Equal(t, "# config\nname: api # the name\n", string(out))
+}
+
+func TestNodeDiff(t *testing.T) {
+	var before, after yaml.Node
+	assert.NoError(t, yaml.Load([]byte("size: 0x10\nname: a\n"), &before))
+	assert.NoError(t, yaml.Load([]byte("name: b\nsize: 16\n"), &after))
+
+	changes, err := yaml.Diff(&before, &after)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(changes))
+	assert.Equal(t, yaml.ChangeModified, changes[0].Kind)
+	assert.Equal(t, "$.name", changes[0].Path)
+	ordered, err := yaml.Diff(&before, &after, yaml.DiffKeyOrder())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(ordered))
+	assert.Equal(t, "--- before\n+++ after\n@@ changed $.name (-L2.C7 +L1.C7) @@\n-a\n+b\n",
+		yaml.UnifiedDiff(changes, "before", "after"))
+}
+
+func TestNodeMerge(t *testing.T) {
+	var base, overlay yaml.Node
+	assert.NoError(t, yaml.Load([]byte("# base\nports: [80]\nenv: {A: 1}\n"), &base))
+	assert.NoError(t, yaml.Load([]byte("ports: [443]\nenv: {B: 2}\n"), &overlay))
+
+	merged, err := yaml.Merge(&base, &overlay, yaml.MergeRule("ports", yaml.MergeAppend))
+	assert.NoError(t, err)
+	out, err := yaml.Dump(merged)
+	assert.NoError(t, err)
+	assert.Equal(t, "# base\nports: [80, 443]\nenv: {A: 1, B: 2}\n", string(out))
+}
+
+func TestNodeJSONPatch(t *testing.T) {
+	var a, b yaml.Node
+	assert.NoError(t, yaml.Load([]byte("name: web # the name\nreplicas: 1\n"), &a))
+	assert.NoError(t, yaml.Load([]byte("name: api\nreplicas: 1\n"), &b))
+
+	patch, err := yaml.CreatePatch(&a, &b)
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"op":"replace","path":"/name","value":"api"}]`, string(patch))
+
+	assert.NoError(t, yaml.ApplyJSONPatch(&a, patch))
+	assert.NoError(t, yaml.ApplyMergePatch(&a, []byte(`{"replicas": 3}`)))
+	out, err := yaml.Dump(&a)
+	assert.NoError(t, err)
+	assert.Equal(t, "name: api # the name\nreplicas: 3\n", string(out))
+}
+
+func TestNodeWalk(t *testing.T) {
+	var doc yaml.Node
+	assert.NoError(t, yaml.Load([]byte("db:\n  password: !secret hunter2\n  hosts: [a, b]\n"), &doc))
+
+	var paths []string
+	err := yaml.Walk(&doc, yaml.VisitorFunc(func(c *yaml.Cursor) yaml.WalkAction {
+		if c.Node.Kind == yaml.ScalarNode {
+			paths = append(paths, c.Path)
+		}
+		if c.Node.Tag == "!secret" {
+			c.Replace(&yaml.Node{Kind: yaml.ScalarNode, Value: "<redacted>"})
+		}
+		return yaml.WalkContinue
+	}))
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"$.db.password", "$.db.hosts[0]", "$.db.hosts[1]"}, paths)
+	out, err := yaml.Dump(&doc)
+	assert.NoError(t, err)
+	assert.Equal(t, "db:\n  password: <redacted>\n  hosts: [a, b]\n", string(out))
+}
@@ -3,6 +3,12 @@
 
 package yaml
 
+import (
+	"reflect"
+
+	"go.yaml.in/yaml/v4/internal/libyaml"
+)
+
 // LimitPlugin configures safety limits for YAML parsing.
 //
 // When registered, CheckDepth is called on each nesting depth increase,
@@ -23,3 +29,178 @@ type LimitPlugin interface {
 	// Return an error to abort construction.
 	CheckAlias(aliasCount, constructCount int) error
 }
+
+// ResourceLimitPlugin caps the resources used to load untrusted YAML.
+//
+// When registered, the limits it returns are enforced while reading,
+// scanning and composing the input. Exceeding one stops loading with a
+// *[LoadError] at the position where the limit was exceeded.
+//
+// Example usage:
+//
+//	import "go.yaml.in/yaml/v4/plugin/limit"
+//	loader := yaml.NewLoader(r, yaml.WithPlugin(limit.New(limit.MaxInputBytes(1<<20))))
+type ResourceLimitPlugin interface {
+	// ResourceLimits returns the limits to enforce. Zero fields keep the
+	// limits set by plugins registered earlier, and are not limited
+	// otherwise.
+	ResourceLimits() ResourceLimits
+}
+
+// ResourceLimits caps the resources used to load a YAML stream:
+//   - InputBytes: bytes of input in the stream
+//   - ScalarLength: bytes in a single scalar value
+//   - Nodes: nodes in a document, including aliases
+//   - MappingKeys: keys in a single mapping
+//   - Documents: documents in the stream
+//   - Anchors: anchors defined in a document
+//
+// A zero field means no limit, which is the default.
+type ResourceLimits = libyaml.ResourceLimits
+
+// ResolverPlugin gives plain scalars implicit application tags, such as
+// !duration for 10s or !semver for 1.2.3.
+//
+// When registered, ResolveTag is called for each plain scalar that would
+// otherwise resolve to a string, so standard types such as integers and
+// booleans keep their meaning. When dumping, values it would tag are
+// written without their tag, and strings it would tag are quoted. When
+// several resolver plugins are registered, a scalar gets the tag of the
+// first one, in registration order, that doesn't return "".
+//
+// Example usage:
+//
+//	import "go.yaml.in/yaml/v4/plugin/tags"
+//	reg := tags.New()
+//	tags.Scalar(reg, "!duration", time.ParseDuration, formatDuration)
+//	reg.Implicit("!duration", regexp.MustCompile(`^[0-9]+(ns|us|ms|s|m|h)$`))
+//	yaml.Load(data, &v, yaml.WithPlugin(reg))
+type ResolverPlugin interface {
+	// ResolveTag returns the tag of a plain scalar, or "" to leave it a
+	// string.
+	ResolveTag(value string) string
+}
+
+// TagPlugin constructs and represents values of application tags, such as
+// !duration or !semver.
+//
+// When registered, ConstructTag is called for each node whose tag is not a
+// standard YAML tag, and RepresentTag is called for each value being
+// dumped. Tags it produces are kept on output, so tagged values round-trip
+// through Dump and Load. When several tag plugins are registered, each value
+// is offered to them in registration order until one handles it.
+//
+// Example usage:
+//
+//	import "go.yaml.in/yaml/v4/plugin/tags"
+//	reg := tags.New()
+//	tags.Scalar(reg, "!duration", time.ParseDuration, func(d time.Duration) (string, error) {
+//		return d.String(), nil
+//	})
+//	yaml.Load(data, &v, yaml.WithPlugin(reg))
+type TagPlugin interface {
+	// ConstructTag constructs n into out and reports whether it handled
+	// the node. Nodes it doesn't handle are constructed as usual.
+	ConstructTag(ctx *TagContext, n *Node, out reflect.Value) (bool, error)
+
+	// RepresentTag returns the node representing in, or nil to let the
+	// value be represented as usual.
+	RepresentTag(ctx *TagContext, in reflect.Value) (*Node, error)
+}
+
+// TagContext gives a [TagPlugin] access to the Load or Dump call using it.
+// Its Load and Dump methods construct and represent the contents of tagged
+// collections with the same options.
+type TagContext = libyaml.TagContext
+
+// NodePlugin rewrites documents as node trees.
+//
+// When registered, TransformNode is called with each document node after
+// its tags are resolved and before it is constructed into Go values when
+// loading, and after it is represented and before inferable tags are
+// removed when dumping. Plugins can change the document in place or return
+// a new one, for example to expand ${ENV} references, include other files
+// or strip keys. Nodes they add without a tag are resolved as usual.
+//
+// Example usage:
+//
+//	type stripPrivate struct{}
+//
+//	func (stripPrivate) TransformNode(ctx *yaml.NodeContext, doc *yaml.Node) (*yaml.Node, error) {
+//		m := doc.Content[0]
+//		for i := len(m.Content) - 2; i >= 0; i -= 2 {
+//			if strings.HasPrefix(m.Content[i].Value, "_") {
+//				m.Content = append(m.Content[:i], m.Content[i+2:]...)
+//			}
+//		}
+//		return doc, nil
+//	}
+//
+//	yaml.Load(data, &v, yaml.WithPlugin(stripPrivate{}))
+type NodePlugin interface {
+	// TransformNode returns the document node to go on with, or nil to
+	// go on with doc after changing it in place. Return an error to abort
+	// loading or dumping.
+	TransformNode(ctx *NodeContext, doc *Node) (*Node, error)
+}
+
+// NodeContext tells a [NodePlugin] whether the document is being loaded or
+// dumped. Its LoadIncluded method loads the files a document includes with
+// the options of the Load call.
+type NodeContext = libyaml.NodeContext
+
+// EventPlugin observes and rewrites the YAML event stream.
+//
+// When registered, ProcessEvent is called for each event between the parser
+// and the composer when loading, and between the serializer and the emitter
+// when dumping. The stream start and end events are passed on as they are.
+// Plugins can drop, replace or inject events, to redact values, rewrite
+// tags or accept legacy syntax, as long as the events they pass on still
+// form a valid stream.
+//
+// Example usage:
+//
+//	type redact struct{ next bool }
+//
+//	func (r *redact) ProcessEvent(ctx *yaml.EventContext, ev yaml.Event) ([]yaml.Event, error) {
+//		if r.next && ev.Type == yaml.ScalarEvent {
+//			ev.Value = []byte("***")
+//		}
+//		r.next = ev.Type == yaml.ScalarEvent && string(ev.Value) == "password"
+//		return []yaml.Event{ev}, nil
+//	}
+//
+//	yaml.Load(data, &v, yaml.WithPlugin(&redact{}))
+type EventPlugin interface {
+	// ProcessEvent returns the events to pass on in place of ev: none to
+	// drop it, ev itself to keep it, or several to inject events before
+	// or after it. Return an error to abort loading or dumping.
+	ProcessEvent(ctx *EventContext, ev Event) ([]Event, error)
+}
+
+// EventContext tells an [EventPlugin] whether events are being loaded or
+// dumped, and makes events to inject from nodes with its NodeEvents method.
+type EventContext = libyaml.EventContext
+
+// Event is an event of the YAML event stream, such as a scalar or the
+// start of a mapping. Tags are in their long form, such as
+// tag:yaml.org,2002:str.
+type Event = libyaml.Event
+
+// EventType is the type of an [Event].
+type EventType = libyaml.EventType
+
+// EventType constants for the events seen by an [EventPlugin].
+const (
+	StreamStartEvent   = libyaml.STREAM_START_EVENT
+	StreamEndEvent     = libyaml.STREAM_END_EVENT
+	DocumentStartEvent = libyaml.DOCUMENT_START_EVENT
+	DocumentEndEvent   = libyaml.DOCUMENT_END_EVENT
+	AliasEvent         = libyaml.ALIAS_EVENT
+	ScalarEvent        = libyaml.SCALAR_EVENT
+	SequenceStartEvent = libyaml.SEQUENCE_START_EVENT
+	SequenceEndEvent   = libyaml.SEQUENCE_END_EVENT
+	MappingStartEvent  = libyaml.MAPPING_START_EVENT
+	MappingEndEvent    = libyaml.MAPPING_END_EVENT
+	TailCommentEvent   = libyaml.TAIL_COMMENT_EVENT
+)
@@ -11,6 +11,16 @@
 //
 // Limit plugin (plugin/limit):
 //   - Configurable depth and alias expansion limits
+//   - Resource limits for input size, scalar length and node counts
+//
+// Tags plugin (plugin/tags):
+//   - Registry of application tags such as !duration or !semver
+//
+// Interpolate plugin (plugin/interpolate):
+//   - ${VAR} and ${VAR:-default} references expanded while loading
+//
+// Include plugin (plugin/include):
+//   - !include, !include-text and !include-glob from a sandboxed fs.FS
 //
 // # Usage
 //
@@ -26,6 +36,6 @@
 //
 // Plugin interfaces use public types and can be implemented by external
 // packages.
-// Implement the relevant plugin interface (e.g., LimitPlugin) and register
+// Implement the relevant plugin interface (e.g., LimitPlugin or TagPlugin) and register
 // with WithPlugin.
 package plugin
@@ -0,0 +1,270 @@
+// Copyright 2026 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package include provides file inclusion for go-yaml.
+//
+// The include plugin replaces tagged scalars with the content of other
+// files while loading:
+//
+//	database: !include db.yaml          # The document in db.yaml
+//	motd: !include-text motd.txt        # The text of motd.txt as a string
+//	services: !include-glob svc/*.yaml  # The documents of the matching files
+//
+// Files are read from a user-supplied [fs.FS], which sandboxes the
+// includes: paths outside of it are errors. Relative paths are resolved
+// from the directory of the including file, and absolute paths from the
+// root of the file system.
+//
+// # Usage
+//
+//	import (
+//	    "os"
+//
+//	    "go.yaml.in/yaml/v4"
+//	    "go.yaml.in/yaml/v4/plugin/include"
+//	)
+//
+//	yaml.Load(data, &cfg, yaml.WithPlugin(include.New(os.DirFS("config"))))
+//
+// Included files are loaded with the options of the Load call, and count
+// against its resource limits as part of the including document, so
+// limit.MaxDocuments, limit.MaxNodes and limit.MaxInputBytes bound the
+// work includes can cause. Include cycles are errors.
+//
+// Included nodes keep their positions in the files they come from, and
+// errors while including name the file they happened in.
+package include
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"go.yaml.in/yaml/v4/internal/libyaml"
+)
+
+// Node is an alias for the YAML node type.
+// See [yaml.Node] for documentation.
+type Node = libyaml.Node
+
+// NodeContext is an alias for the context passed to node plugins.
+// See [yaml.NodeContext] for documentation.
+type NodeContext = libyaml.NodeContext
+
+// Tags handled by the plugin.
+const (
+	IncludeTag     = "!include"      // The document of a YAML file
+	IncludeTextTag = "!include-text" // The text of a file, as a string
+	IncludeGlobTag = "!include-glob" // A sequence of the documents of matching YAML files
+)
+
+// Plugin implements [yaml.NodePlugin] by replacing include tags in loaded
+// documents with the content of the files they name. A Plugin is safe for
+// concurrent use.
+type Plugin struct {
+	fsys fs.FS
+	dir  string
+}
+
+// Option configures a [Plugin].
+type Option func(*Plugin)
+
+// New creates an include plugin reading files from fsys.
+func New(fsys fs.FS, opts ...Option) *Plugin {
+	p := &Plugin{fsys: fsys, dir: "."}
+	for _, o := range opts {
+		o(p)
+	}
+	return p
+}
+
+// Dir sets the directory of fsys that relative paths in the documents
+// being loaded are resolved from. The default is the root of fsys.
+func Dir(dir string) Option {
+	return func(p *Plugin) {
+		p.dir = path.Clean(dir)
+	}
+}
+
+// NewFromYAML creates an include plugin from a YAML config map.
+// Keys: "root" (string, required), the directory to read files from.
+func NewFromYAML(cfg map[string]any) (*Plugin, error) {
+	var root string
+	for key, val := range cfg {
+		switch key {
+		case "root":
+			s, ok := val.(string)
+			if !ok || s == "" {
+				return nil, fmt.Errorf("include: root must be a non-empty string, got %v", val)
+			}
+			root = s
+		default:
+			return nil, fmt.Errorf("include: unknown key %q", key)
+		}
+	}
+	if root == "" {
+		return nil, errors.New("include: root is required")
+	}
+	return New(os.DirFS(root)), nil
+}
+
+// TransformNode implements [yaml.NodePlugin]. It replaces the include tags
+// of documents being loaded in place, and leaves documents being dumped
+// alone.
+func (p *Plugin) TransformNode(ctx *NodeContext, doc *Node) (*Node, error) {
+	if ctx.Stage != libyaml.ResolverStage {
+		return nil, nil
+	}
+	return nil, p.includeTree(ctx, doc, nil)
+}
+
+// includeTree replaces the include tags of the tree n in place. stack holds
+// the files being included, innermost last; n comes from the last one, or
+// from the document being loaded when stack is empty. Aliased nodes are
+// replaced where they are anchored, so aliases are not followed.
+func (p *Plugin) includeTree(ctx *NodeContext, n *Node, stack []string) error {
+	switch n.Tag {
+	case IncludeTag, IncludeTextTag, IncludeGlobTag:
+		return p.include(ctx, n, stack)
+	}
+	if n.Kind == libyaml.AliasNode {
+		return nil
+	}
+	for _, child := range n.Content {
+		if err := p.includeTree(ctx, child, stack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// include replaces the include node n with the content it names.
+func (p *Plugin) include(ctx *NodeContext, n *Node, stack []string) error {
+	if n.Kind != libyaml.ScalarNode {
+		return p.errorf(n, stack, nil, "%s needs a path, got a %s", n.Tag, kindName(n.Kind))
+	}
+	name, err := p.resolve(n, stack)
+	if err != nil {
+		return err
+	}
+	switch n.Tag {
+	case IncludeTextTag:
+		data, err := fs.ReadFile(p.fsys, name)
+		if err != nil {
+			return p.errorf(n, stack, err, "%v", err)
+		}
+		n.Tag, n.Value, n.Style = "!!str", string(data), 0
+	case IncludeTag:
+		content, err := p.load(ctx, n, name, stack)
+		if err != nil {
+			return err
+		}
+		replace(n, content)
+	case IncludeGlobTag:
+		names, err := fs.Glob(p.fsys, name)
+		if err != nil {
+			return p.errorf(n, stack, err, "%v", err)
+		}
+		seq := &Node{Kind: libyaml.SequenceNode, Tag: "!!seq"}
+		for _, name := range names {
+			content, err := p.load(ctx, n, name, stack)
+			if err != nil {
+				return err
+			}
+			seq.Content = append(seq.Content, content)
+		}
+		seq.Line, seq.Column = n.Line, n.Column
+		replace(n, seq)
+	}
+	return nil
+}
+
+// resolve returns the name in the file system of the path in the include
+// node n.
+func (p *Plugin) resolve(n *Node, stack []string) (string, error) {
+	if n.Value == "" {
+		return "", p.errorf(n, stack, nil, "empty %s path", n.Tag)
+	}
+	var name string
+	switch {
+	case path.IsAbs(n.Value):
+		name = path.Clean(strings.TrimLeft(n.Value, "/"))
+	case len(stack) > 0:
+		name = path.Join(path.Dir(stack[len(stack)-1]), n.Value)
+	default:
+		name = path.Join(p.dir, n.Value)
+	}
+	if !fs.ValidPath(name) {
+		return "", p.errorf(n, stack, nil, "%s path %q is outside the file system", n.Tag, n.Value)
+	}
+	return name, nil
+}
+
+// load returns the content of the single document in the file name,
+// included by the node n, with its own includes replaced.
+func (p *Plugin) load(ctx *NodeContext, n *Node, name string, stack []string) (*Node, error) {
+	for i, f := range stack {
+		if f == name {
+			cycle := strings.Join(append(stack[i:len(stack):len(stack)], name), " -> ")
+			return nil, p.errorf(n, stack, nil, "include cycle: %s", cycle)
+		}
+	}
+	data, err := fs.ReadFile(p.fsys, name)
+	if err != nil {
+		return nil, p.errorf(n, stack, err, "%v", err)
+	}
+	docs, err := ctx.LoadIncluded(name, data)
+	if err != nil {
+		return nil, err
+	}
+	switch len(docs) {
+	case 0:
+		return &Node{Kind: libyaml.ScalarNode, Tag: "!!null", Line: 1, Column: 1}, nil
+	case 1:
+	default:
+		return nil, p.errorf(n, stack, nil, "%s has %d documents, %s needs one", name, len(docs), n.Tag)
+	}
+	content := docs[0].Content[0]
+	stack = append(stack[:len(stack):len(stack)], name)
+	if err := p.includeTree(ctx, content, stack); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// replace replaces the include node n in place with content, so that
+// aliases of n see the included content. n keeps its anchor.
+func replace(n, content *Node) {
+	anchor := n.Anchor
+	*n = *content
+	if anchor != "" {
+		n.Anchor = anchor
+	}
+}
+
+// errorf returns a load error at the include node n, naming the file n
+// comes from when it is an included file.
+func (p *Plugin) errorf(n *Node, stack []string, cause error, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if len(stack) > 0 {
+		msg = stack[len(stack)-1] + ": " + msg
+	}
+	return libyaml.NewLoadError(libyaml.ResolverStage, msg,
+		libyaml.Mark{Line: n.Line, Column: n.Column}, cause)
+}
+
+// kindName returns the name of a node kind for error messages.
+func kindName(k libyaml.Kind) string {
+	switch k {
+	case libyaml.MappingNode:
+		return "mapping"
+	case libyaml.SequenceNode:
+		return "sequence"
+	case libyaml.AliasNode:
+		return "alias"
+	}
+	return "scalar"
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package include_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"go.yaml.in/yaml/v4"
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+	"go.yaml.in/yaml/v4/plugin/include"
+	"go.yaml.in/yaml/v4/plugin/interpolate"
+	"go.yaml.in/yaml/v4/plugin/limit"
+)
+
+var files = fstest.MapFS{
+	"app.yaml":              {Data: []byte("name: app\ndb: !include conf/db.yaml\n")},
+	"conf/db.yaml":          {Data: []byte("host: localhost\nport: 5432\nauth: !include secret/auth.yaml\n")},
+	"conf/secret/auth.yaml": {Data: []byte("user: admin\n")},
+	"motd.txt":              {Data: []byte("Welcome!\n")},
+	"svc/a.yaml":            {Data: []byte("name: a\n")},
+	"svc/b.yaml":            {Data: []byte("name: b\n")},
+	"empty.yaml":            {Data: []byte("")},
+	"multi.yaml":            {Data: []byte("a: 1\n---\nb: 2\n")},
+	"bad.yaml":              {Data: []byte("a: 1\nb: [2\n")},
+	"loop/a.yaml":           {Data: []byte("b: !include b.yaml\n")},
+	"loop/b.yaml":           {Data: []byte("a: !include /loop/a.yaml\n")},
+	"env.yaml":              {Data: []byte("host: ${HOST}\n")},
+}
+
+func load(t *testing.T, src string, opts ...yaml.Option) (any, error) {
+	t.Helper()
+	var v any
+	opts = append([]yaml.Option{yaml.WithPlugin(include.New(files))}, opts...)
+	err := yaml.Load([]byte(src), &v, opts...)
+	return v, err
+}
+
+func TestInclude(t *testing.T) {
+	v, err := load(t, "app: !include app.yaml\n"+
+		"motd: !include-text motd.txt\n"+
+		"services: !include-glob svc/*.yaml\n"+
+		"none: !include-glob nothing/*.yaml\n"+
+		"empty: !include empty.yaml\n")
+	assert.NoError(t, err)
+	assert.DeepEqual(t, map[string]any{
+		"app": map[string]any{
+			"name": "app",
+			"db": map[string]any{
+				"host": "localhost",
+				"port": 5432,
+				"auth": map[string]any{"user": "admin"},
+			},
+		},
+		"motd":     "Welcome!\n",
+		"services": []any{map[string]any{"name": "a"}, map[string]any{"name": "b"}},
+		"none":     []any{},
+		"empty":    nil,
+	}, v)
+}
+
+func TestIncludeAliases(t *testing.T) {
+	v, err := load(t, "a: &db !include conf/secret/auth.yaml\nb: *db\n")
+	assert.NoError(t, err)
+	auth := map[string]any{"user": "admin"}
+	assert.DeepEqual(t, map[string]any{"a": auth, "b": auth}, v)
+}
+
+func TestIncludeDir(t *testing.T) {
+	var v map[string]any
+	p := include.New(files, include.Dir("conf"))
+	assert.NoError(t, yaml.Load([]byte("db: !include db.yaml\n"), &v, yaml.WithPlugin(p)))
+	assert.Equal(t, "localhost", v["db"].(map[string]any)["host"])
+}
+
+func TestIncludePositions(t *testing.T) {
+	var doc yaml.Node
+	err := yaml.Load([]byte("x: 1\ndb: !include conf/db.yaml\n"), &doc, yaml.WithPlugin(include.New(files)))
+	assert.NoError(t, err)
+	db := doc.Content[0].Content[3]
+	assert.Equal(t, "port", db.Content[2].Value)
+	assert.Equal(t, 2, db.Content[2].Line)
+	assert.Equal(t, 1, db.Content[2].Column)
+}
+
+func TestIncludeErrors(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{"a: !include missing.yaml\n",
+			"go-yaml load error in resolver at L1.C4: open missing.yaml: file does not exist"},
+		{"a: !include ../etc/passwd\n",
+			`go-yaml load error in resolver at L1.C4: !include path "../etc/passwd" is outside the file system`},
+		{"a: !include ''\n",
+			"go-yaml load error in resolver at L1.C4: empty !include path"},
+		{"a: !include [x]\n",
+			"go-yaml load error in resolver at L1.C4: !include needs a path, got a sequence"},
+		{"a: !include multi.yaml\n",
+			"go-yaml load error in resolver at L1.C4: multi.yaml has 2 documents, !include needs one"},
+		{"a: !include bad.yaml\n",
+			"go-yaml load error in parser (while parsing a flow sequence) at L2.C4-L3.C1: bad.yaml: did not find expected ',' or ']'"},
+		{"a: !include loop/a.yaml\n",
+			"go-yaml load error in resolver at L1.C4: loop/b.yaml: include cycle: loop/a.yaml -> loop/b.yaml -> loop/a.yaml"},
+		{"a: !include-glob '['\n",
+			"go-yaml load error in resolver at L1.C4: syntax error in pattern"},
+	}
+	for _, tt := range tests {
+		_, err := load(t, tt.src)
+		var lerr *yaml.LoadError
+		assert.Truef(t, errors.As(err, &lerr), "Load(%q) error %v is not a LoadError", tt.src, err)
+		assert.Equalf(t, tt.want, err.Error(), "Load(%q)", tt.src)
+	}
+
+	_, err := load(t, "a: !include missing.yaml\n")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+}
+
+func TestIncludeLimits(t *testing.T) {
+	_, err := load(t, "- !include svc/a.yaml\n- !include svc/b.yaml\n",
+		yaml.WithPlugin(limit.New(limit.MaxDocuments(2))))
+	assert.ErrorMatches(t, `go-yaml load error in composer at L1\.C1: svc/b\.yaml: exceeded max document count of 2`, err)
+
+	_, err = load(t, "!include conf/db.yaml\n",
+		yaml.WithPlugin(limit.New(limit.MaxNodes(8))))
+	assert.ErrorMatches(t, `.*conf/secret/auth\.yaml: exceeded max node count of 8`, err)
+
+	_, err = load(t, "!include app.yaml\n",
+		yaml.WithPlugin(limit.New(limit.MaxInputBytes(64))))
+	assert.ErrorMatches(t, `go-yaml load error in reader at L1\.C10: conf/db\.yaml: exceeded max input size of 64 bytes`, err)
+
+	_, err = load(t, "!include app.yaml\n",
+		yaml.WithPlugin(limit.New(limit.MaxDocuments(4), limit.MaxNodes(32), limit.MaxInputBytes(256))))
+	assert.NoError(t, err)
+}
+
+func TestIncludeInterpolate(t *testing.T) {
+	lookup := func(name string) (string, bool) { return "db", name == "HOST" }
+	v, err := load(t, "!include env.yaml\n",
+		yaml.WithPlugin(interpolate.New(interpolate.Lookup(lookup))))
+	assert.NoError(t, err)
+	assert.DeepEqual(t, map[string]any{"host": "db"}, v)
+}
+
+func TestIncludeDump(t *testing.T) {
+	var n yaml.Node
+	assert.NoError(t, yaml.Load([]byte("a: !include motd.txt\n"), &n))
+	out, err := yaml.Dump(&n, yaml.WithPlugin(include.New(files)))
+	assert.NoError(t, err)
+	assert.Equal(t, "a: !include motd.txt\n", string(out))
+}
+
+func TestNewFromYAML(t *testing.T) {
+	opts, err := yaml.OptsYAML("plugin:\n  include:\n    root: testdata\n")
+	assert.NoError(t, err)
+	var v map[string]any
+	err = yaml.Load([]byte("a: !include missing.yaml\n"), &v, opts)
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	_, err = include.NewFromYAML(map[string]any{})
+	assert.ErrorMatches(t, "include: root is required", err)
+	_, err = include.NewFromYAML(map[string]any{"root": 1})
+	assert.ErrorMatches(t, "include: root must be a non-empty string, got 1", err)
+	_, err = include.NewFromYAML(map[string]any{"dir": "x"})
+	assert.ErrorMatches(t, `include: unknown key "dir"`, err)
+}
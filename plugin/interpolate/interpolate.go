@@ -0,0 +1,283 @@
+// Copyright 2026 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package interpolate provides variable interpolation for go-yaml.
+//
+// The interpolate plugin expands ${VAR} and ${VAR:-default} references in
+// scalars while loading, with values from the environment or from a lookup
+// function. $$ stands for a literal $.
+//
+// # Usage
+//
+//	import (
+//	    "go.yaml.in/yaml/v4"
+//	    "go.yaml.in/yaml/v4/plugin/interpolate"
+//	)
+//
+//	// Expand environment variables; undefined ones expand to ""
+//	yaml.Load(data, &cfg, yaml.WithPlugin(interpolate.New()))
+//
+//	// Report undefined variables without a default as errors
+//	yaml.Load(data, &cfg, yaml.WithPlugin(interpolate.New(interpolate.Strict())))
+//
+//	// Expand variables from a map
+//	vars := map[string]string{"HOST": "db"}
+//	yaml.Load(data, &cfg, yaml.WithPlugin(interpolate.New(interpolate.Lookup(
+//	    func(name string) (string, bool) { v, ok := vars[name]; return v, ok },
+//	))))
+//
+// Plain scalars are resolved again after expansion, so port: ${PORT} loads
+// as an integer when PORT is a number. Quoted and explicitly tagged scalars
+// keep their tag.
+//
+// # Node Trees
+//
+// The plugin only changes documents being loaded; dumping is not affected.
+// To keep the original text of a document, load it into a [yaml.Node]
+// without the plugin and expand a copy with [Plugin.ExpandNode]:
+//
+//	var doc yaml.Node
+//	yaml.Load(data, &doc)
+//	expanded, err := interpolate.New().ExpandNode(&doc)
+//	...
+//	expanded.Load(&cfg)
+//	yaml.Dump(&doc) // Still has the ${VAR} references
+package interpolate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v4/internal/libyaml"
+)
+
+// Node is an alias for the YAML node type.
+// See [yaml.Node] for documentation.
+type Node = libyaml.Node
+
+// NodeContext is an alias for the context passed to node plugins.
+// See [yaml.NodeContext] for documentation.
+type NodeContext = libyaml.NodeContext
+
+// ErrUndefined is the cause of errors for undefined variables in strict
+// mode.
+var ErrUndefined = errors.New("undefined variable")
+
+// Plugin implements [yaml.NodePlugin] by expanding variable references in
+// the scalars of loaded documents. A Plugin is safe for concurrent use.
+type Plugin struct {
+	lookup func(name string) (string, bool)
+	strict bool
+}
+
+// Option configures a [Plugin].
+type Option func(*Plugin)
+
+// New creates an interpolate plugin with the given options.
+// With no options, it looks variables up with [os.LookupEnv] and expands
+// undefined ones to "".
+func New(opts ...Option) *Plugin {
+	p := &Plugin{lookup: os.LookupEnv}
+	for _, o := range opts {
+		o(p)
+	}
+	return p
+}
+
+// Lookup sets the function that looks up the value of a variable. It
+// reports whether the variable is defined.
+func Lookup(fn func(name string) (string, bool)) Option {
+	return func(p *Plugin) {
+		p.lookup = fn
+	}
+}
+
+// Strict makes undefined variables without a default an error, instead of
+// expanding them to "".
+func Strict() Option {
+	return func(p *Plugin) {
+		p.strict = true
+	}
+}
+
+// NewFromYAML creates an interpolate plugin from a YAML config map.
+// Keys: "strict" (bool). Variables are looked up in the environment.
+func NewFromYAML(cfg map[string]any) (*Plugin, error) {
+	var opts []Option
+	for key, val := range cfg {
+		switch key {
+		case "strict":
+			strict, ok := val.(bool)
+			if !ok {
+				return nil, fmt.Errorf("interpolate: strict must be bool, got %T", val)
+			}
+			if strict {
+				opts = append(opts, Strict())
+			}
+		default:
+			return nil, fmt.Errorf("interpolate: unknown key %q", key)
+		}
+	}
+	return New(opts...), nil
+}
+
+// TransformNode implements [yaml.NodePlugin]. It expands the scalars of
+// documents being loaded in place, and leaves documents being dumped
+// alone.
+func (p *Plugin) TransformNode(ctx *NodeContext, doc *Node) (*Node, error) {
+	if ctx.Stage != libyaml.ResolverStage {
+		return nil, nil
+	}
+	return nil, p.expandTree(doc)
+}
+
+// ExpandNode returns a copy of the node tree n with the variable references
+// of its scalars expanded. n is not changed.
+func (p *Plugin) ExpandNode(n *Node) (*Node, error) {
+	copies := make(map[*Node]*Node)
+	out := copyTree(n, copies)
+	if err := p.expandTree(out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// copyTree returns a deep copy of n. Aliases point to the copies of their
+// anchored nodes, which are recorded in copies.
+func copyTree(n *Node, copies map[*Node]*Node) *Node {
+	if n == nil {
+		return nil
+	}
+	if c, ok := copies[n]; ok {
+		return c
+	}
+	c := *n
+	copies[n] = &c
+	if n.Content != nil {
+		c.Content = make([]*Node, len(n.Content))
+		for i, child := range n.Content {
+			c.Content[i] = copyTree(child, copies)
+		}
+	}
+	if n.Alias != nil {
+		c.Alias = copyTree(n.Alias, copies)
+	}
+	return &c
+}
+
+// expandTree expands the scalars of the tree n in place. Aliased nodes are
+// expanded where they are anchored, so aliases are not followed.
+func (p *Plugin) expandTree(n *Node) error {
+	switch n.Kind {
+	case libyaml.ScalarNode:
+		return p.expandScalar(n)
+	case libyaml.AliasNode:
+		return nil
+	}
+	for _, child := range n.Content {
+		if err := p.expandTree(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandScalar expands the value of the scalar n. Plain scalars whose value
+// changed lose their resolved tag, so that they are resolved again.
+func (p *Plugin) expandScalar(n *Node) error {
+	if !strings.Contains(n.Value, "$") {
+		return nil
+	}
+	value, err := p.Expand(n.Value)
+	if err != nil {
+		return libyaml.NewLoadError(libyaml.ResolverStage, err.Error(),
+			libyaml.Mark{Line: n.Line, Column: n.Column}, err)
+	}
+	if value == n.Value {
+		return nil
+	}
+	n.Value = value
+	const notPlain = libyaml.TaggedStyle | libyaml.SingleQuotedStyle |
+		libyaml.DoubleQuotedStyle | libyaml.LiteralStyle | libyaml.FoldedStyle
+	if n.Style&notPlain == 0 {
+		n.Tag = ""
+	}
+	return nil
+}
+
+// Expand returns s with its variable references expanded.
+func (p *Plugin) Expand(s string) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(s, '$')
+		if i < 0 || i == len(s)-1 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		b.WriteString(s[:i])
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			s = s[i+2:]
+		case '{':
+			end := closingBrace(s, i+2)
+			if end < 0 {
+				return "", fmt.Errorf("unterminated variable reference %q", s[i:])
+			}
+			value, err := p.expandRef(s[i+2 : end])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(value)
+			s = s[end+1:]
+		default:
+			b.WriteByte('$')
+			s = s[i+1:]
+		}
+	}
+}
+
+// closingBrace returns the index of the brace closing the reference that
+// starts at s[start], allowing nested references in defaults, or -1.
+func closingBrace(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '$':
+			i++
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// expandRef returns the value of the reference ref, the text between ${
+// and }.
+func (p *Plugin) expandRef(ref string) (string, error) {
+	name, def, hasDefault := strings.Cut(ref, ":-")
+	if name == "" {
+		return "", fmt.Errorf("empty variable name in \"${%s}\"", ref)
+	}
+	if strings.ContainsAny(name, "${} \t") {
+		return "", fmt.Errorf("invalid variable name %q", name)
+	}
+	value, ok := p.lookup(name)
+	switch {
+	case ok && value != "":
+		return value, nil
+	case hasDefault:
+		return p.Expand(def)
+	case !ok && p.strict:
+		return "", fmt.Errorf("%w %q", ErrUndefined, name)
+	}
+	return value, nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package interpolate_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"go.yaml.in/yaml/v4"
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+	"go.yaml.in/yaml/v4/plugin/interpolate"
+)
+
+var vars = map[string]string{
+	"HOST":  "db.local",
+	"PORT":  "5432",
+	"EMPTY": "",
+	"NAME":  "HOST",
+}
+
+func lookup(name string) (string, bool) {
+	v, ok := vars[name]
+	return v, ok
+}
+
+func TestExpand(t *testing.T) {
+	p := interpolate.New(interpolate.Lookup(lookup))
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"${HOST}:${PORT}", "db.local:5432"},
+		{"${MISSING}", ""},
+		{"${MISSING:-fallback}", "fallback"},
+		{"${EMPTY:-fallback}", "fallback"},
+		{"${HOST:-fallback}", "db.local"},
+		{"${MISSING:-${HOST}}", "db.local"},
+		{"${MISSING:-}", ""},
+		{"$$HOME and $${HOST}", "$HOME and ${HOST}"},
+		{"costs $5 or $", "costs $5 or $"},
+		{"${MISSING:-a}b}", "ab}"},
+	}
+	for _, tt := range tests {
+		got, err := p.Expand(tt.in)
+		assert.NoErrorf(t, err, "Expand(%q)", tt.in)
+		assert.Equalf(t, tt.want, got, "Expand(%q)", tt.in)
+	}
+
+	for in, want := range map[string]string{
+		"${HOST":    `unterminated variable reference "${HOST"`,
+		"${}":       `empty variable name in "${}"`,
+		"${:-x}":    `empty variable name in "${:-x}"`,
+		"${A B}":    `invalid variable name "A B"`,
+		"${A:-${B}": `unterminated variable reference "${A:-${B}"`,
+	} {
+		_, err := p.Expand(in)
+		assert.ErrorMatchesf(t, regexp.QuoteMeta(want), err, "Expand(%q)", in)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	p := interpolate.New(interpolate.Lookup(lookup), interpolate.Strict())
+	got, err := p.Expand("${EMPTY}${MISSING:-x}")
+	assert.NoError(t, err)
+	assert.Equal(t, "x", got)
+
+	_, err = p.Expand("${MISSING}")
+	assert.ErrorMatches(t, `undefined variable "MISSING"`, err)
+	assert.True(t, errors.Is(err, interpolate.ErrUndefined))
+}
+
+type config struct {
+	URL     string `yaml:"url"`
+	Port    any    `yaml:"port"`
+	Quoted  any    `yaml:"quoted"`
+	Tagged  any    `yaml:"tagged"`
+	Key     string `yaml:"key"`
+	Aliased string `yaml:"aliased"`
+}
+
+func TestLoad(t *testing.T) {
+	p := interpolate.New(interpolate.Lookup(lookup))
+	src := "url: http://${HOST}:${PORT}/\n" +
+		"port: ${PORT}\n" +
+		"quoted: '${PORT}'\n" +
+		"tagged: !!str ${PORT}\n" +
+		"key: &k ${NAME}\n" +
+		"aliased: *k\n"
+	var cfg config
+	assert.NoError(t, yaml.Load([]byte(src), &cfg, yaml.WithPlugin(p)))
+	assert.Equal(t, config{
+		URL:     "http://db.local:5432/",
+		Port:    5432,
+		Quoted:  "5432",
+		Tagged:  "5432",
+		Key:     "HOST",
+		Aliased: "HOST",
+	}, cfg)
+
+	// Dumping is not affected.
+	out, err := yaml.Dump(map[string]string{"a": "${HOST}"}, yaml.WithPlugin(p))
+	assert.NoError(t, err)
+	assert.Equal(t, "a: ${HOST}\n", string(out))
+}
+
+func TestLoadError(t *testing.T) {
+	p := interpolate.New(interpolate.Lookup(lookup), interpolate.Strict())
+	var cfg config
+	err := yaml.Load([]byte("url: x\nport: ${PORT}/${NOPE}\n"), &cfg, yaml.WithPlugin(p))
+	var lerr *yaml.LoadError
+	assert.True(t, errors.As(err, &lerr))
+	assert.Equal(t, 2, lerr.Mark.Line)
+	assert.Equal(t, 7, lerr.Mark.Column)
+	assert.Equal(t, `go-yaml load error in resolver at L2.C7: undefined variable "NOPE"`, err.Error())
+	assert.True(t, errors.Is(err, interpolate.ErrUndefined))
+}
+
+func TestExpandNode(t *testing.T) {
+	p := interpolate.New(interpolate.Lookup(lookup))
+	src := "url: ${HOST}\nport: ${PORT}\nkey: &k ${HOST}\naliased: *k\n"
+	var doc yaml.Node
+	assert.NoError(t, yaml.Load([]byte(src), &doc))
+
+	expanded, err := p.ExpandNode(&doc)
+	assert.NoError(t, err)
+	var cfg config
+	assert.NoError(t, expanded.Load(&cfg))
+	assert.Equal(t, config{URL: "db.local", Port: 5432, Key: "db.local", Aliased: "db.local"}, cfg)
+
+	// The original document keeps its text.
+	out, err := yaml.Dump(&doc)
+	assert.NoError(t, err)
+	assert.Equal(t, src, string(out))
+}
+
+func TestNewFromYAML(t *testing.T) {
+	opts, err := yaml.OptsYAML("plugin:\n  interpolate:\n    strict: true\n")
+	assert.NoError(t, err)
+	var v map[string]string
+	err = yaml.Load([]byte("a: ${GO_YAML_INTERPOLATE_TEST_UNSET}\n"), &v, opts)
+	assert.True(t, errors.Is(err, interpolate.ErrUndefined))
+
+	_, err = interpolate.NewFromYAML(map[string]any{"strict": "yes"})
+	assert.ErrorMatches(t, "interpolate: strict must be bool, got string", err)
+	_, err = interpolate.NewFromYAML(map[string]any{"lookup": nil})
+	assert.ErrorMatches(t, `interpolate: unknown key "lookup"`, err)
+}
@@ -8,6 +8,11 @@
 // By default, go-yaml enforces conservative limits to prevent DoS attacks.
 // This plugin lets you relax or tighten those limits for your use case.
 //
+// It can also cap the input size, the length of scalars, the number of
+// documents in a stream, and the number of nodes, anchors and mapping keys
+// in a document. These limits are off by default; services loading
+// untrusted YAML should set them.
+//
 // # Usage
 //
 //	import (
@@ -24,6 +29,13 @@
 //	// Custom depth limit
 //	loader := yaml.NewLoader(data, yaml.WithPlugin(limit.New(limit.DepthValue(50))))
 //
+//	// Resource limits for untrusted input
+//	loader := yaml.NewLoader(data, yaml.WithPlugin(limit.New(
+//	    limit.MaxInputBytes(1<<20),
+//	    limit.MaxScalarLength(64<<10),
+//	    limit.MaxNodes(100000),
+//	)))
+//
 // # Third-Party Plugins
 //
 // You can implement [yaml.LimitPlugin] directly instead of using this package:
@@ -32,6 +44,9 @@
 //	func (s *StrictLimit) CheckDepth(depth int, ctx *yaml.DepthContext) error { ... }
 //	func (s *StrictLimit) CheckAlias(aliasCount, constructCount int) error { ... }
 //	yaml.NewLoader(data, yaml.WithPlugin(&StrictLimit{}))
+//
+// Resource limits come from [yaml.ResourceLimitPlugin], which can be
+// implemented the same way.
 package limit
 
 import (
@@ -44,6 +59,11 @@ import (
 // See [yaml.DepthContext] for field documentation.
 type DepthContext = libyaml.DepthContext
 
+// ResourceLimits is an alias for the limits returned by
+// [Plugin.ResourceLimits]. See [yaml.ResourceLimits] for field
+// documentation.
+type ResourceLimits = libyaml.ResourceLimits
+
 // Plugin implements configurable safety limits for YAML parsing.
 type Plugin struct {
 	depthLimit    *int
@@ -52,6 +72,7 @@ type Plugin struct {
 	aliasLimit    *int
 	aliasDisabled bool
 	aliasFn       func(int, int) error
+	limits        ResourceLimits
 }
 
 // Option configures a [Plugin].
@@ -121,6 +142,54 @@ func AliasFunc(fn func(aliasCount, constructCount int) error) Option {
 	}
 }
 
+// MaxInputBytes sets the maximum number of bytes of input in a stream.
+// 0 means no limit.
+func MaxInputBytes(n int) Option {
+	return func(p *Plugin) {
+		p.limits.InputBytes = n
+	}
+}
+
+// MaxScalarLength sets the maximum number of bytes in a scalar value.
+// 0 means no limit.
+func MaxScalarLength(n int) Option {
+	return func(p *Plugin) {
+		p.limits.ScalarLength = n
+	}
+}
+
+// MaxNodes sets the maximum number of nodes in a document, including
+// aliases. 0 means no limit.
+func MaxNodes(n int) Option {
+	return func(p *Plugin) {
+		p.limits.Nodes = n
+	}
+}
+
+// MaxMappingKeys sets the maximum number of keys in a mapping.
+// 0 means no limit.
+func MaxMappingKeys(n int) Option {
+	return func(p *Plugin) {
+		p.limits.MappingKeys = n
+	}
+}
+
+// MaxDocuments sets the maximum number of documents in a stream.
+// 0 means no limit.
+func MaxDocuments(n int) Option {
+	return func(p *Plugin) {
+		p.limits.Documents = n
+	}
+}
+
+// MaxAnchors sets the maximum number of anchors defined in a document.
+// 0 means no limit.
+func MaxAnchors(n int) Option {
+	return func(p *Plugin) {
+		p.limits.Anchors = n
+	}
+}
+
 // CheckDepth implements [yaml.LimitPlugin].
 func (p *Plugin) CheckDepth(depth int, ctx *DepthContext) error {
 	if p.depthFn != nil {
@@ -155,13 +224,43 @@ func (p *Plugin) CheckAlias(aliasCount, constructCount int) error {
 	return libyaml.DefaultAliasCheck(aliasCount, constructCount)
 }
 
+// ResourceLimits implements [yaml.ResourceLimitPlugin].
+func (p *Plugin) ResourceLimits() ResourceLimits {
+	return p.limits
+}
+
+// resourceOptions maps the NewFromYAML keys of resource limits to their
+// options.
+var resourceOptions = map[string]func(int) Option{
+	"input-bytes":   MaxInputBytes,
+	"scalar-length": MaxScalarLength,
+	"nodes":         MaxNodes,
+	"mapping-keys":  MaxMappingKeys,
+	"documents":     MaxDocuments,
+	"anchors":       MaxAnchors,
+}
+
 // NewFromYAML creates a limit plugin from a YAML config map.
 // Keys: "depth" (int or null), "alias" (int or null).
 // Null values disable the corresponding check.
+// Resource limit keys: "input-bytes", "scalar-length", "nodes",
+// "mapping-keys", "documents" and "anchors" (int, or 0 or null for no
+// limit).
 // Omitted keys use defaults.
 func NewFromYAML(cfg map[string]any) (*Plugin, error) {
 	var opts []Option
 	for key, val := range cfg {
+		if opt, ok := resourceOptions[key]; ok {
+			if val == nil {
+				continue
+			}
+			n, ok := val.(int)
+			if !ok || n < 0 {
+				return nil, fmt.Errorf("limit: %s must be a non-negative int or null, got %v", key, val)
+			}
+			opts = append(opts, opt(n))
+			continue
+		}
 		switch key {
 		case "depth":
 			if val == nil {
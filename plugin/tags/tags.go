@@ -0,0 +1,305 @@
+// Copyright 2026 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tags provides a registry of application tags for go-yaml.
+//
+// A registry maps tags such as !duration, !regexp or !semver to functions
+// that construct Go values from tagged scalars, sequences or mappings, and
+// that represent those values again when dumping. Tagged values therefore
+// round-trip through Dump and Load.
+//
+// # Usage
+//
+//	import (
+//	    "go.yaml.in/yaml/v4"
+//	    "go.yaml.in/yaml/v4/plugin/tags"
+//	)
+//
+//	reg := tags.New()
+//	tags.Scalar(reg, "!duration", time.ParseDuration,
+//	    func(d time.Duration) (string, error) { return d.String(), nil })
+//	tags.Sequence(reg, "!point", func(xy []float64) (Point, error) {
+//	    if len(xy) != 2 {
+//	        return Point{}, errors.New("a point needs two coordinates")
+//	    }
+//	    return Point{xy[0], xy[1]}, nil
+//	}, func(p Point) ([]float64, error) { return []float64{p.X, p.Y}, nil })
+//
+//	yaml.Load(data, &cfg, yaml.WithPlugin(reg))
+//	yaml.Dump(&cfg, yaml.WithPlugin(reg))
+//
+// A tagged node is constructed by the registry when the target can hold
+// the registered type: a value of that type, a pointer to one, or an
+// interface it implements. Other targets are constructed as usual, ignoring
+// the tag. Values of a registered type are always dumped with their tag.
+//
+// # Third-Party Plugins
+//
+// You can implement [yaml.TagPlugin] directly instead of using this package.
+package tags
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"go.yaml.in/yaml/v4/internal/libyaml"
+)
+
+// Node is an alias for the YAML node type.
+// See [yaml.Node] for documentation.
+type Node = libyaml.Node
+
+// TagContext is an alias for the context passed to tag plugins.
+// See [yaml.TagContext] for documentation.
+type TagContext = libyaml.TagContext
+
+// Registry implements [yaml.TagPlugin] for the tags registered with
+// [Scalar], [Sequence] and [Mapping], and [yaml.ResolverPlugin] for the
+// patterns registered with [Registry.Implicit].
+//
+// Register all tags before using the registry; a Registry is not safe for
+// concurrent registration.
+type Registry struct {
+	byTag  map[string]*handler
+	byType map[reflect.Type]*handler
+
+	implicit []implicitTag
+}
+
+// implicitTag is a pattern registered with Implicit.
+type implicitTag struct {
+	tag     string
+	pattern *regexp.Regexp
+}
+
+// handler holds the functions registered for one tag.
+type handler struct {
+	tag       string
+	kind      libyaml.Kind
+	typ       reflect.Type
+	construct func(ctx *TagContext, n *Node) (reflect.Value, error)
+	represent func(ctx *TagContext, in reflect.Value) (*Node, error)
+}
+
+// New creates an empty registry.
+func New() *Registry {
+	return &Registry{
+		byTag:  make(map[string]*handler),
+		byType: make(map[reflect.Type]*handler),
+	}
+}
+
+// Scalar registers tag for values of type T written as scalars.
+//
+// construct parses the scalar value. represent formats a value; it may be
+// nil for tags that are only loaded. Registering a tag or type again
+// replaces the earlier registration.
+func Scalar[T any](r *Registry, tag string, construct func(string) (T, error), represent func(T) (string, error)) {
+	h := &handler{kind: libyaml.ScalarNode}
+	h.construct = func(ctx *TagContext, n *Node) (reflect.Value, error) {
+		v, err := construct(n.Value)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot construct %s `%s`: %w", h.tag, n.Value, err)
+		}
+		return reflect.ValueOf(&v).Elem(), nil
+	}
+	if represent != nil {
+		h.represent = func(ctx *TagContext, in reflect.Value) (*Node, error) {
+			s, err := represent(in.Interface().(T))
+			if err != nil {
+				return nil, err
+			}
+			return &Node{Kind: libyaml.ScalarNode, Value: s}, nil
+		}
+	}
+	register[T](r, tag, h)
+}
+
+// Sequence registers tag for values of type T written as sequences.
+//
+// The items of a tagged sequence are loaded into a []E, with the options of
+// the running Load, and passed to construct. The items returned by
+// represent are dumped as the tagged sequence; represent may be nil for
+// tags that are only loaded.
+func Sequence[T, E any](r *Registry, tag string, construct func([]E) (T, error), represent func(T) ([]E, error)) {
+	h := &handler{kind: libyaml.SequenceNode}
+	h.construct = func(ctx *TagContext, n *Node) (reflect.Value, error) {
+		var items []E
+		if err := ctx.Load(untagged(n), &items); err != nil {
+			return reflect.Value{}, err
+		}
+		v, err := construct(items)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot construct %s: %w", h.tag, err)
+		}
+		return reflect.ValueOf(&v).Elem(), nil
+	}
+	if represent != nil {
+		h.represent = func(ctx *TagContext, in reflect.Value) (*Node, error) {
+			items, err := represent(in.Interface().(T))
+			if err != nil {
+				return nil, err
+			}
+			if items == nil {
+				items = []E{}
+			}
+			return ctx.Dump(items)
+		}
+	}
+	register[T](r, tag, h)
+}
+
+// Mapping registers tag for values of type T written as mappings.
+//
+// A tagged mapping is loaded into an M, usually a struct or a map, with the
+// options of the running Load, and passed to construct. The M returned by
+// represent is dumped as the tagged mapping; represent may be nil for tags
+// that are only loaded.
+func Mapping[T, M any](r *Registry, tag string, construct func(M) (T, error), represent func(T) (M, error)) {
+	h := &handler{kind: libyaml.MappingNode}
+	h.construct = func(ctx *TagContext, n *Node) (reflect.Value, error) {
+		var m M
+		if err := ctx.Load(untagged(n), &m); err != nil {
+			return reflect.Value{}, err
+		}
+		v, err := construct(m)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot construct %s: %w", h.tag, err)
+		}
+		return reflect.ValueOf(&v).Elem(), nil
+	}
+	if represent != nil {
+		h.represent = func(ctx *TagContext, in reflect.Value) (*Node, error) {
+			m, err := represent(in.Interface().(T))
+			if err != nil {
+				return nil, err
+			}
+			node, err := ctx.Dump(m)
+			if err == nil && node.Kind != libyaml.MappingNode {
+				return nil, fmt.Errorf("%s must be represented as a mapping, not a %s", h.tag, kindName(node.Kind))
+			}
+			return node, err
+		}
+	}
+	register[T](r, tag, h)
+}
+
+// Implicit makes plain scalars that match pattern resolve to tag, as if
+// the tag was written in the document. Only scalars that would otherwise
+// resolve to strings are matched, so numbers, booleans and nulls keep
+// their meaning. Patterns are tried in the order they were registered.
+//
+// Values dumped with tag whose text matches pattern are written without
+// the tag, and strings that match are quoted.
+func (r *Registry) Implicit(tag string, pattern *regexp.Regexp) {
+	r.implicit = append(r.implicit, implicitTag{normalizeTag(tag), pattern})
+}
+
+// normalizeTag returns tag in the short form used by nodes.
+func normalizeTag(tag string) string {
+	if tag == "" {
+		panic("tags: empty tag")
+	}
+	if strings.HasPrefix(tag, "tag:yaml.org,2002:") {
+		return "!!" + tag[len("tag:yaml.org,2002:"):]
+	}
+	return tag
+}
+
+// register adds h to r for tag and type T.
+func register[T any](r *Registry, tag string, h *handler) {
+	tag = normalizeTag(tag)
+	h.tag = tag
+	h.typ = reflect.TypeOf((*T)(nil)).Elem()
+	if old, ok := r.byTag[tag]; ok && r.byType[old.typ] == old {
+		delete(r.byType, old.typ)
+	}
+	r.byTag[tag] = h
+	if h.represent != nil {
+		r.byType[h.typ] = h
+	}
+}
+
+// untagged returns a copy of n without its tag, so that loading its
+// contents doesn't come back to the registry.
+func untagged(n *Node) *Node {
+	c := *n
+	c.Tag = ""
+	return &c
+}
+
+// kindName returns the name of a node kind for error messages.
+func kindName(k libyaml.Kind) string {
+	switch k {
+	case libyaml.ScalarNode:
+		return "scalar"
+	case libyaml.SequenceNode:
+		return "sequence"
+	case libyaml.MappingNode:
+		return "mapping"
+	case libyaml.AliasNode:
+		return "alias"
+	}
+	return "document"
+}
+
+// ConstructTag implements [yaml.TagPlugin].
+func (r *Registry) ConstructTag(ctx *TagContext, n *Node, out reflect.Value) (bool, error) {
+	h, ok := r.byTag[n.ShortTag()]
+	if !ok || !assignable(h.typ, out.Type()) {
+		return false, nil
+	}
+	if n.Kind != h.kind {
+		return true, fmt.Errorf("%s must be a %s, not a %s", h.tag, kindName(h.kind), kindName(n.Kind))
+	}
+	v, err := h.construct(ctx, n)
+	if err != nil {
+		return true, err
+	}
+	for !v.Type().AssignableTo(out.Type()) {
+		if out.IsNil() {
+			out.Set(reflect.New(out.Type().Elem()))
+		}
+		out = out.Elem()
+	}
+	out.Set(v)
+	return true, nil
+}
+
+// assignable reports whether a value of type typ can be stored in a value
+// of type out, possibly through pointers.
+func assignable(typ, out reflect.Type) bool {
+	for !typ.AssignableTo(out) {
+		if out.Kind() != reflect.Pointer {
+			return false
+		}
+		out = out.Elem()
+	}
+	return true
+}
+
+// RepresentTag implements [yaml.TagPlugin].
+func (r *Registry) RepresentTag(ctx *TagContext, in reflect.Value) (*Node, error) {
+	h, ok := r.byType[in.Type()]
+	if !ok {
+		return nil, nil
+	}
+	node, err := h.represent(ctx, in)
+	if err != nil {
+		return nil, fmt.Errorf("cannot represent %s as %s: %w", in.Type(), h.tag, err)
+	}
+	node.Tag = h.tag
+	return node, nil
+}
+
+// ResolveTag implements [yaml.ResolverPlugin].
+func (r *Registry) ResolveTag(value string) string {
+	for _, it := range r.implicit {
+		if it.pattern.MatchString(value) {
+			return it.tag
+		}
+	}
+	return ""
+}
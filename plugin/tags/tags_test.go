@@ -0,0 +1,237 @@
+// Copyright 2026 The go-yaml Project Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package tags_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"go.yaml.in/yaml/v4"
+	"go.yaml.in/yaml/v4/internal/testutil/assert"
+	"go.yaml.in/yaml/v4/plugin/tags"
+)
+
+type point struct{ X, Y float64 }
+
+type version struct {
+	Major, Minor int
+}
+
+type config struct {
+	Timeout time.Duration  `yaml:"timeout"`
+	Pattern *regexp.Regexp `yaml:"pattern"`
+	Origin  point          `yaml:"origin"`
+	Version version        `yaml:"version"`
+	Steps   []point        `yaml:"steps,flow"`
+	Extra   any            `yaml:"extra"`
+}
+
+func newRegistry() *tags.Registry {
+	reg := tags.New()
+	tags.Scalar(reg, "!duration", time.ParseDuration, func(d time.Duration) (string, error) {
+		return d.String(), nil
+	})
+	tags.Scalar(reg, "!regexp", regexp.Compile, func(re *regexp.Regexp) (string, error) {
+		return re.String(), nil
+	})
+	tags.Sequence(reg, "!point", func(xy []float64) (point, error) {
+		if len(xy) != 2 {
+			return point{}, errors.New("a point needs two coordinates")
+		}
+		return point{xy[0], xy[1]}, nil
+	}, func(p point) ([]float64, error) {
+		return []float64{p.X, p.Y}, nil
+	})
+	tags.Mapping(reg, "!semver", func(m map[string]int) (version, error) {
+		return version{m["major"], m["minor"]}, nil
+	}, func(v version) (map[string]int, error) {
+		return map[string]int{"major": v.Major, "minor": v.Minor}, nil
+	})
+	return reg
+}
+
+func TestRegistryRoundTrip(t *testing.T) {
+	reg := newRegistry()
+	src := strings.Join([]string{
+		"timeout: !duration 1m30s",
+		"pattern: !regexp ^a+$",
+		"origin: !point [1, 2.5]",
+		"version: !semver {major: 1, minor: 4}",
+		"steps: [!point [0, 0], !point [3, 4]]",
+		"extra: !duration 2h0m0s",
+		"",
+	}, "\n")
+
+	var cfg config
+	assert.NoError(t, yaml.Load([]byte(src), &cfg, yaml.WithPlugin(reg)))
+	assert.Equal(t, 90*time.Second, cfg.Timeout)
+	assert.True(t, cfg.Pattern.MatchString("aaa"))
+	assert.Equal(t, point{1, 2.5}, cfg.Origin)
+	assert.Equal(t, version{1, 4}, cfg.Version)
+	assert.DeepEqual(t, []point{{0, 0}, {3, 4}}, cfg.Steps)
+	assert.Equal(t, any(2*time.Hour), cfg.Extra)
+
+	out, err := yaml.Dump(&cfg, yaml.WithPlugin(reg))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"timeout: !duration 1m30s",
+		"pattern: !regexp ^a+$",
+		"origin: !point",
+		"- 1",
+		"- 2.5",
+		"version: !semver",
+		"  major: 1",
+		"  minor: 4",
+		"steps: [!point [0, 0], !point [3, 4]]",
+		"extra: !duration 2h0m0s",
+		"",
+	}, "\n"), string(out))
+
+	var again config
+	assert.NoError(t, yaml.Load(out, &again, yaml.WithPlugin(reg)))
+	assert.Equal(t, cfg.Origin, again.Origin)
+	assert.Equal(t, cfg.Pattern.String(), again.Pattern.String())
+}
+
+func TestRegistryNested(t *testing.T) {
+	reg := newRegistry()
+	tags.Sequence(reg, "!schedule", func(ds []time.Duration) ([]time.Duration, error) {
+		return ds, nil
+	}, nil)
+
+	var v any
+	err := yaml.Load([]byte("!schedule [!duration 1s, !duration 1m]"), &v, yaml.WithPlugin(reg))
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []time.Duration{time.Second, time.Minute}, v)
+
+	// Load-only tags are dumped as their type would be.
+	out, err := yaml.Dump(v, yaml.WithPlugin(reg))
+	assert.NoError(t, err)
+	assert.Equal(t, "- !duration 1s\n- !duration 1m0s\n", string(out))
+}
+
+func TestRegistryFallback(t *testing.T) {
+	reg := newRegistry()
+
+	// Targets that can't hold the registered type ignore the tag.
+	var s struct{ Timeout string }
+	assert.NoError(t, yaml.Load([]byte("timeout: !duration 1m"), &s, yaml.WithPlugin(reg)))
+	assert.Equal(t, "1m", s.Timeout)
+
+	// Unregistered tags are constructed as usual.
+	var v map[string]any
+	assert.NoError(t, yaml.Load([]byte("a: !other 1m"), &v, yaml.WithPlugin(reg)))
+	assert.DeepEqual(t, map[string]any{"a": "1m"}, v)
+
+	// Pointers are allocated.
+	var p struct{ Timeout *time.Duration }
+	assert.NoError(t, yaml.Load([]byte("timeout: !duration 1m"), &p, yaml.WithPlugin(reg)))
+	assert.Equal(t, time.Minute, *p.Timeout)
+}
+
+func TestRegistryChain(t *testing.T) {
+	durations := tags.New()
+	tags.Scalar(durations, "!duration", time.ParseDuration, func(d time.Duration) (string, error) {
+		return d.String(), nil
+	})
+	points := tags.New()
+	tags.Sequence(points, "!point", func(xy []float64) (point, error) {
+		return point{xy[0], xy[1]}, nil
+	}, func(p point) ([]float64, error) {
+		return []float64{p.X, p.Y}, nil
+	})
+
+	// Each node and value is offered to the registries in turn.
+	src := "timeout: !duration 1m30s\norigin: !point [1, 2]\n"
+	var cfg struct {
+		Timeout time.Duration `yaml:"timeout"`
+		Origin  point         `yaml:"origin,flow"`
+	}
+	assert.NoError(t, yaml.Load([]byte(src), &cfg, yaml.WithPlugin(durations, points)))
+	assert.Equal(t, 90*time.Second, cfg.Timeout)
+	assert.Equal(t, point{1, 2}, cfg.Origin)
+
+	out, err := yaml.Dump(&cfg, yaml.WithPlugin(durations), yaml.WithPlugin(points))
+	assert.NoError(t, err)
+	assert.Equal(t, src, string(out))
+}
+
+func TestRegistryErrors(t *testing.T) {
+	reg := newRegistry()
+	var cfg config
+
+	err := yaml.Load([]byte("timeout: !duration soon\n"), &cfg, yaml.WithPlugin(reg))
+	assert.ErrorMatches(t, "yaml: construct errors: line 1: cannot construct !duration `soon`: .*", err)
+
+	err = yaml.Load([]byte("origin: !point [1]\n"), &cfg, yaml.WithPlugin(reg))
+	assert.ErrorMatches(t, "yaml: construct errors: line 1: cannot construct !point: a point needs two coordinates", err)
+
+	err = yaml.Load([]byte("origin: !point 1\n"), &cfg, yaml.WithPlugin(reg))
+	assert.ErrorMatches(t, "yaml: construct errors: line 1: !point must be a sequence, not a scalar", err)
+
+	err = yaml.Load([]byte("origin: !point [1, x]\n"), &cfg, yaml.WithPlugin(reg))
+	assert.ErrorMatches(t, "yaml: construct errors: line 1: cannot construct !!str `x` into float64", err)
+
+	tags.Scalar(reg, "!duration", time.ParseDuration, func(d time.Duration) (string, error) {
+		return "", fmt.Errorf("no durations today")
+	})
+	_, err = yaml.Dump(time.Second, yaml.WithPlugin(reg))
+	assert.ErrorMatches(t, ".*cannot represent time.Duration as !duration: no durations today", err)
+}
+
+type semver struct{ Major, Minor, Patch int }
+
+func TestRegistryImplicit(t *testing.T) {
+	reg := newRegistry()
+	tags.Scalar(reg, "!semver", func(s string) (semver, error) {
+		var v semver
+		_, err := fmt.Sscanf(s, "%d.%d.%d", &v.Major, &v.Minor, &v.Patch)
+		return v, err
+	}, func(v semver) (string, error) {
+		return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch), nil
+	})
+	reg.Implicit("!duration", regexp.MustCompile(`^[0-9]+(ns|us|ms|s|m|h)$`))
+	reg.Implicit("!semver", regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+$`))
+
+	src := "timeout: 10s\nversion: 1.2.3\ncount: 10\nname: '10s'\nother: 10s\n"
+	var v map[string]any
+	assert.NoError(t, yaml.Load([]byte(src), &v, yaml.WithPlugin(reg)))
+	assert.DeepEqual(t, map[string]any{
+		"timeout": 10 * time.Second,
+		"version": semver{1, 2, 3},
+		"count":   10,
+		"name":    "10s",
+		"other":   10 * time.Second,
+	}, v)
+
+	// Targets that can't hold the type read the plain value as a string.
+	var s struct {
+		Timeout time.Duration
+		Version string
+	}
+	assert.NoError(t, yaml.Load([]byte(src), &s, yaml.WithPlugin(reg)))
+	assert.Equal(t, 10*time.Second, s.Timeout)
+	assert.Equal(t, "1.2.3", s.Version)
+
+	out, err := yaml.Dump(v, yaml.WithPlugin(reg))
+	assert.NoError(t, err)
+	assert.Equal(t, "count: 10\nname: '10s'\nother: 10s\ntimeout: 10s\nversion: 1.2.3\n", string(out))
+
+	// Without the plugin, the string is not quoted.
+	out, err = yaml.Dump(map[string]string{"name": "10s"})
+	assert.NoError(t, err)
+	assert.Equal(t, "name: 10s\n", string(out))
+
+	// Explicit tags are kept.
+	var n yaml.Node
+	assert.NoError(t, yaml.Load([]byte("a: !duration 1m\nb: 1m\n"), &n, yaml.WithPlugin(reg)))
+	assert.Equal(t, "!duration", n.Content[0].Content[3].Tag)
+	out, err = yaml.Dump(&n, yaml.WithPlugin(reg))
+	assert.NoError(t, err)
+	assert.Equal(t, "a: !duration 1m\nb: 1m\n", string(out))
+}
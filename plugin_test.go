@@ -5,6 +5,7 @@ package yaml_test
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 	"testing"
 
@@ -95,3 +96,232 @@ func TestDefaultBehavior_HasLimit(t *testing.T) {
 		t.Fatal("Expected error from default depth limits, got nil")
 	}
 }
+
+// envRefs gives $NAME scalars the !env tag.
+type envRefs struct{}
+
+func (envRefs) ResolveTag(value string) string {
+	if strings.HasPrefix(value, "$") {
+		return "!env"
+	}
+	return ""
+}
+
+func TestWithPlugin_Resolver(t *testing.T) {
+	var n yaml.Node
+	err := yaml.Load([]byte("a: $HOME\nb: '$HOME'\nc: 10\n"), &n, yaml.WithPlugin(envRefs{}))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	m := n.Content[0]
+	for i, want := range []string{"!env", "!!str", "!!int"} {
+		if tag := m.Content[2*i+1].Tag; tag != want {
+			t.Errorf("Expected %s to resolve to %s, got %s", m.Content[2*i].Value, want, tag)
+		}
+	}
+
+	out, err := yaml.Dump(&n, yaml.WithPlugin(envRefs{}))
+	if err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+	if string(out) != "a: $HOME\nb: '$HOME'\nc: 10\n" {
+		t.Errorf("Unexpected output: %q", out)
+	}
+
+	out, err = yaml.Dump(map[string]string{"a": "$HOME"}, yaml.WithPlugin(envRefs{}))
+	if err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+	if string(out) != "a: '$HOME'\n" {
+		t.Errorf("Unexpected output: %q", out)
+	}
+}
+
+// caretRefs gives ^NAME and $NAME scalars the !ref tag.
+type caretRefs struct{}
+
+func (caretRefs) ResolveTag(value string) string {
+	if strings.HasPrefix(value, "^") || strings.HasPrefix(value, "$") {
+		return "!ref"
+	}
+	return ""
+}
+
+func TestWithPlugin_ResolverChain(t *testing.T) {
+	var n yaml.Node
+	err := yaml.Load([]byte("a: $HOME\nb: ^home\nc: home\n"), &n, yaml.WithPlugin(envRefs{}, caretRefs{}))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	m := n.Content[0]
+	for i, want := range []string{"!env", "!ref", "!!str"} {
+		if tag := m.Content[2*i+1].Tag; tag != want {
+			t.Errorf("Expected %s to resolve to %s, got %s", m.Content[2*i].Value, want, tag)
+		}
+	}
+
+	out, err := yaml.Dump(map[string]string{"a": "$HOME", "b": "^home"}, yaml.WithPlugin(envRefs{}), yaml.WithPlugin(caretRefs{}))
+	if err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+	if string(out) != "a: '$HOME'\nb: '^home'\n" {
+		t.Errorf("Unexpected output: %q", out)
+	}
+}
+
+// streamLimits is a third-party resource limit plugin.
+type streamLimits struct{}
+
+func (streamLimits) ResourceLimits() yaml.ResourceLimits {
+	return yaml.ResourceLimits{Documents: 2}
+}
+
+func TestWithPlugin_ResourceLimits(t *testing.T) {
+	loader, err := yaml.NewLoader(strings.NewReader("1\n---\n2\n---\n3\n"), yaml.WithPlugin(streamLimits{}))
+	if err != nil {
+		t.Fatalf("NewLoader failed: %v", err)
+	}
+	var v int
+	for i := 0; i < 2; i++ {
+		if err := loader.Load(&v); err != nil {
+			t.Fatalf("Load %d failed: %v", i+1, err)
+		}
+	}
+	err = loader.Load(&v)
+	var lerr *yaml.LoadError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("Expected a *LoadError, got: %v", err)
+	}
+	if lerr.Error() != "go-yaml load error in composer at L4.C1: exceeded max document count of 2" {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+}
+
+func TestWithPlugin_ResourceLimitsMerge(t *testing.T) {
+	// A later limit plugin only replaces the limits it sets.
+	var v any
+	err := yaml.Load([]byte("[1, 2, 3]"), &v,
+		yaml.WithPlugin(limit.New(limit.MaxNodes(3))),
+		yaml.WithPlugin(limit.New(limit.DepthValue(50))),
+		yaml.WithPlugin(limit.New(limit.MaxAnchors(1))))
+	if err == nil || err.Error() != "go-yaml load error in composer at L1.C8: exceeded max node count of 3" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	err = yaml.Load([]byte("[1, 2, 3]"), &v,
+		yaml.WithPlugin(limit.New(limit.MaxNodes(3))),
+		yaml.WithPlugin(limit.New(limit.MaxNodes(4))))
+	if err != nil {
+		t.Errorf("Load failed: %v", err)
+	}
+}
+
+func TestLimitNewFromYAML_ResourceLimits(t *testing.T) {
+	p, err := limit.NewFromYAML(map[string]any{"nodes": 10, "anchors": nil, "documents": 0})
+	if err != nil {
+		t.Fatalf("NewFromYAML failed: %v", err)
+	}
+	if got := p.ResourceLimits(); got != (yaml.ResourceLimits{Nodes: 10}) {
+		t.Errorf("Unexpected limits: %+v", got)
+	}
+
+	_, err = limit.NewFromYAML(map[string]any{"nodes": -1})
+	if err == nil || err.Error() != "limit: nodes must be a non-negative int or null, got -1" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+// redactPlugin replaces the values of password keys.
+type redactPlugin struct {
+	next   bool
+	stages []yaml.Stage
+}
+
+func (r *redactPlugin) ProcessEvent(ctx *yaml.EventContext, ev yaml.Event) ([]yaml.Event, error) {
+	if len(r.stages) == 0 || r.stages[len(r.stages)-1] != ctx.Stage {
+		r.stages = append(r.stages, ctx.Stage)
+	}
+	if r.next && ev.Type == yaml.ScalarEvent {
+		ev.Value = []byte("***")
+	}
+	r.next = ev.Type == yaml.ScalarEvent && string(ev.Value) == "password"
+	return []yaml.Event{ev}, nil
+}
+
+func TestWithPlugin_Event(t *testing.T) {
+	r := &redactPlugin{}
+	var v map[string]string
+	err := yaml.Load([]byte("user: bob\npassword: hunter2\n"), &v, yaml.WithPlugin(r))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if v["user"] != "bob" || v["password"] != "***" {
+		t.Errorf("Unexpected value: %v", v)
+	}
+
+	out, err := yaml.Dump(map[string]string{"password": "hunter2"}, yaml.WithPlugin(r))
+	if err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+	if string(out) != "password: '***'\n" {
+		t.Errorf("Unexpected output: %q", out)
+	}
+	if len(r.stages) != 2 || r.stages[0] != yaml.ParserStage || r.stages[1] != yaml.SerializerStage {
+		t.Errorf("Unexpected stages: %v", r.stages)
+	}
+}
+
+// stripPrivate removes the keys starting with an underscore from the top
+// mapping.
+type stripPrivate struct{}
+
+func (stripPrivate) TransformNode(ctx *yaml.NodeContext, doc *yaml.Node) (*yaml.Node, error) {
+	m := doc.Content[0]
+	for i := len(m.Content) - 2; i >= 0; i -= 2 {
+		if strings.HasPrefix(m.Content[i].Value, "_") {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+		}
+	}
+	return doc, nil
+}
+
+func TestWithPlugin_Node(t *testing.T) {
+	var v map[string]int
+	err := yaml.Load([]byte("a: 1\n_b: 2\nc: 3\n"), &v, yaml.WithPlugin(stripPrivate{}))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(v) != 2 || v["a"] != 1 || v["c"] != 3 {
+		t.Errorf("Unexpected value: %v", v)
+	}
+
+	out, err := yaml.Dump(map[string]int{"_a": 1, "b": 2}, yaml.WithPlugin(stripPrivate{}))
+	if err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+	if string(out) != "b: 2\n" {
+		t.Errorf("Unexpected output: %q", out)
+	}
+}
+
+// upperPlugin upper-cases scalar values.
+type upperPlugin struct{}
+
+func (upperPlugin) ProcessEvent(ctx *yaml.EventContext, ev yaml.Event) ([]yaml.Event, error) {
+	if ev.Type == yaml.ScalarEvent {
+		ev.Value = bytes.ToUpper(ev.Value)
+	}
+	return []yaml.Event{ev}, nil
+}
+
+func TestWithPlugin_EventChain(t *testing.T) {
+	// Both event plugins see the events, in the order they are registered.
+	var v map[string]string
+	err := yaml.Load([]byte("password: hunter2\n"), &v, yaml.WithPlugin(&redactPlugin{}), yaml.WithPlugin(upperPlugin{}))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if v["PASSWORD"] != "***" {
+		t.Errorf("Unexpected value: %v", v)
+	}
+}
@@ -22,8 +22,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 
 	"go.yaml.in/yaml/v4/internal/libyaml"
+	"go.yaml.in/yaml/v4/plugin/include"
+	"go.yaml.in/yaml/v4/plugin/interpolate"
 	"go.yaml.in/yaml/v4/plugin/limit"
 )
 
@@ -181,6 +184,71 @@ var (
 	// The default is true.
 	WithUniqueKeys = libyaml.WithUniqueKeys
 
+	// WithCollectErrors makes loading continue after errors that leave the
+	// rest of the input readable, so that one *LoadErrors reports all of
+	// them, each with its position: duplicate keys, invalid map keys and
+	// arrays of the wrong length are reported with the type errors, unknown
+	// fields and failing unmarshalers. With WithAllDocuments, loading
+	// continues with the next document after a document with errors.
+	// Syntax errors still stop loading.
+	// When called without arguments, defaults to true.
+	//
+	// The default is false.
+	WithCollectErrors = libyaml.WithCollectErrors
+
+	// WithMaxErrors sets the maximum number of errors reported by a load.
+	// Loading stops once an error beyond the limit is found, and the
+	// returned *LoadErrors ends with a "too many errors" error in its
+	// place. Set to 0 for no limit.
+	//
+	// Example:
+	//
+	//	err := yaml.Load(data, &cfg, yaml.WithKnownFields(),
+	//		yaml.WithCollectErrors(), yaml.WithMaxErrors(20))
+	//
+	// The default is 0.
+	WithMaxErrors = libyaml.WithMaxErrors
+
+	// WithCaseInsensitiveKeys enables or disables case-insensitive matching
+	// of mapping keys to struct fields during loading.
+	//
+	// Exact matches take precedence. Keys of a struct that differ only in
+	// case (such as "key" and "Key") must be matched exactly.
+	// When called without arguments, defaults to true.
+	//
+	// The default is false.
+	WithCaseInsensitiveKeys = libyaml.WithCaseInsensitiveKeys
+
+	// WithDeprecatedKeyFunc sets a function called during loading for each
+	// mapping key that is decoded into a struct field through one of the
+	// alias= keys in its tag, to report old key names:
+	//
+	//	type Config struct {
+	//	    Timeout int `yaml:"timeout,alias=time_out"`
+	//	}
+	//	yaml.Load(data, &cfg, yaml.WithDeprecatedKeyFunc(func(alias, key string, mark yaml.Mark) {
+	//	    log.Printf("line %d: %s is deprecated, use %s", mark.Line, alias, key)
+	//	}))
+	WithDeprecatedKeyFunc = libyaml.WithDeprecatedKeyFunc
+
+	// WithUseNumber makes loading store numbers held in interface values,
+	// such as the values of a map[string]any, as Number rather than int or
+	// float64, like json.Decoder.UseNumber. No precision is lost, and the
+	// Number is dumped back as the same number.
+	// When called without arguments, defaults to true.
+	//
+	// The default is false.
+	WithUseNumber = libyaml.WithUseNumber
+
+	// WithOrderedMaps makes loading store mappings held in interface values,
+	// such as the result of loading into an any, as MapSlice rather than
+	// map[string]any or map[any]any. The keys keep the order of the
+	// document, and dumping the MapSlice writes them in the same order.
+	// When called without arguments, defaults to true.
+	//
+	// The default is false.
+	WithOrderedMaps = libyaml.WithOrderedMaps
+
 	// WithCanonical forces canonical YAML output format.
 	//
 	// When enabled, the encoder outputs strictly canonical YAML with explicit
@@ -229,6 +297,46 @@ var (
 	// When false (default), all collections use block style.
 	WithFlowSimpleCollections = libyaml.WithFlowSimpleCollections
 
+	// WithFieldNaming sets how struct fields without an explicit key in
+	// their yaml tag are named, when loading and dumping.
+	//
+	// Example: with KebabCase, a field MaxRetries maps to "max-retries".
+	//
+	// The default is LowerCase.
+	WithFieldNaming = libyaml.WithFieldNaming
+
+	// WithJSONTags makes loading and dumping read the json tags of struct
+	// fields that have no yaml tag, so types shared with encoding/json need
+	// no duplicate tags.
+	//
+	// The json key and the omitempty, omitzero and string options are
+	// honored, "-" skips the field, and embedded structs without a json key
+	// are inlined. A yaml tag always takes precedence over a json tag.
+	// When called without arguments, defaults to true.
+	//
+	// The default is false.
+	WithJSONTags = libyaml.WithJSONTags
+
+	// WithSchema sets the schema used to resolve the tags of plain scalars
+	// when loading, and to decide which strings must be quoted when
+	// dumping.
+	//
+	// Example: with YAML11Schema, "yes" and "off" load as booleans, 0755
+	// as an octal integer and 1:30 as the base 60 integer 90; with
+	// JSONSchema only null, true, false and JSON numbers are resolved.
+	//
+	// The default is DefaultSchema.
+	WithSchema = libyaml.WithSchema
+
+	// WithSchemaFromVersion makes the %YAML version directive of a
+	// document select its schema: %YAML 1.1 selects YAML11Schema and
+	// %YAML 1.2 selects CoreSchema.
+	// Documents without a directive use the schema set by WithSchema.
+	// When called without arguments, defaults to true.
+	//
+	// The default is false.
+	WithSchemaFromVersion = libyaml.WithSchemaFromVersion
+
 	// WithQuotePreference sets the preferred quote style for strings that
 	// require quoting.
 	//
@@ -245,8 +353,46 @@ var (
 	//   - QuoteDouble: Use double quotes
 	//   - QuoteLegacy: Legacy v2/v3 behavior (mixed quoting)
 	WithQuotePreference = libyaml.WithQuotePreference
+
+	// WithMapKeyOrder sets the order in which the string keys of maps and
+	// MapSlice values are dumped. Keys that are not both strings keep their
+	// natural order, and struct fields keep their declaration order.
+	//
+	// Presets:
+	//   - InsertionKeyOrder: MapSlice order, natural order for maps (default)
+	//   - NaturalKeyOrder: natural number ordering, so "a2" comes before "a10"
+	//   - LexicalKeyOrder: byte-wise comparison
+	//   - PriorityKeyOrder(keys...): the given keys first, then natural order
+	//
+	// Example:
+	//
+	//	yaml.Dump(manifest, yaml.WithMapKeyOrder(
+	//		yaml.PriorityKeyOrder("apiVersion", "kind", "metadata")))
+	WithMapKeyOrder = libyaml.WithMapKeyOrder
 )
 
+// WithDiscriminator registers the concrete types that values of the
+// interface type T are loaded into, by name. A value of type T is loaded
+// from a mapping whose key names a type, or from a node with a local tag
+// naming it. An empty key selects types by tag only. Dumping a value held
+// in a field, slice element or map value of type T writes the key first in
+// its mapping, or the tag when key is empty or the value is not a mapping.
+//
+// Example:
+//
+//	type Backend interface{ Start() error }
+//
+//	opts := yaml.WithDiscriminator("type", map[string]Backend{
+//		"http": &HTTPBackend{},
+//		"grpc": &GRPCBackend{},
+//		"exec": &ExecBackend{},
+//	})
+//	var cfg struct{ Backends []Backend }
+//	yaml.Load([]byte("backends:\n- type: http\n  url: http://a\n- !exec {command: [ls]}\n"), &cfg, opts)
+func WithDiscriminator[T any](key string, types map[string]T) Option {
+	return libyaml.WithDiscriminator(key, types)
+}
+
 // Options combines multiple options into a single Option.
 // This is useful for creating option presets or combining version defaults
 // with custom options.
@@ -277,6 +423,16 @@ type DepthContext = libyaml.DepthContext
 // Each plugin implements one or more plugin interfaces.
 // Currently supported plugin types:
 //   - LimitPlugin: Controls depth and alias expansion limits
+//   - ResourceLimitPlugin: Caps input size, scalar length, and node,
+//     mapping key, document and anchor counts
+//   - TagPlugin: Constructs and represents application tags; several tag
+//     plugins are tried in the order they are registered
+//   - ResolverPlugin: Gives plain scalars implicit application tags; several
+//     resolver plugins are asked in the order they are registered
+//   - EventPlugin: Observes and rewrites the event stream; several event
+//     plugins run in the order they are registered
+//   - NodePlugin: Rewrites documents as node trees; several node plugins
+//     run in the order they are registered
 //
 // Example:
 //
@@ -293,6 +449,27 @@ func WithPlugin(plugins ...any) Option {
 				o.AliasCheck = lp.CheckAlias
 				registered = true
 			}
+			if rp, ok := p.(ResourceLimitPlugin); ok {
+				o.ResourceLimits = libyaml.MergeResourceLimits(o.ResourceLimits, rp.ResourceLimits())
+				registered = true
+			}
+			if tp, ok := p.(TagPlugin); ok {
+				o.TagConstruct = libyaml.ChainTagConstructFuncs(o.TagConstruct, tp.ConstructTag)
+				o.TagRepresent = libyaml.ChainTagRepresentFuncs(o.TagRepresent, tp.RepresentTag)
+				registered = true
+			}
+			if rp, ok := p.(ResolverPlugin); ok {
+				o.ImplicitTag = libyaml.ChainImplicitTagFuncs(o.ImplicitTag, rp.ResolveTag)
+				registered = true
+			}
+			if ep, ok := p.(EventPlugin); ok {
+				o.EventHook = libyaml.ChainEventFuncs(o.EventHook, ep.ProcessEvent)
+				registered = true
+			}
+			if np, ok := p.(NodePlugin); ok {
+				o.NodeHook = libyaml.ChainNodeFuncs(o.NodeHook, np.TransformNode)
+				registered = true
+			}
 			// Future plugin types add cases here (non-exclusive if)
 			if !registered {
 				return fmt.Errorf("yaml: unsupported plugin type: %T", p)
@@ -315,15 +492,30 @@ func WithPlugin(plugins ...any) Option {
 // - explicit-start (bool)
 // - explicit-end (bool)
 // - flow-simple-coll (bool)
+// - map-key-order (string: insertion, natural, lexical)
 // - known-fields (bool)
 // - single-document (bool)
 // - unique-keys (bool)
+// - case-insensitive-keys (bool)
+// - use-number (bool)
+// - ordered-maps (bool)
+// - collect-errors (bool)
+// - max-errors (int)
+// - field-naming (string: lower, snake, kebab, camel, pascal)
+// - json-tags (bool)
+// - schema (string: default, core, json, failsafe, yaml1.1)
+// - schema-from-version (bool)
 // - plugin (map of plugin name to config)
 //
 // The plugin field configures plugins by name. Each key is a plugin
 // name and the value is its configuration map (or null for defaults).
 // Currently supported: "limit" with keys "depth" and "alias" (int
-// or null to disable).
+// or null to disable), and "input-bytes", "scalar-length", "nodes",
+// "mapping-keys", "documents" and "anchors" (int, or 0 or null for no
+// limit); "include" with key "root" (string, the directory to include
+// files from); and "interpolate" with key "strict" (bool). Plugins are
+// registered in the order of their names, so included files are
+// interpolated.
 //
 // Only fields specified in the YAML will override other options when
 // combined. Unspecified fields won't affect other options.
@@ -349,9 +541,19 @@ func OptsYAML(yamlStr string) (Option, error) {
 		ExplicitStart         *bool          `yaml:"explicit-start"`
 		ExplicitEnd           *bool          `yaml:"explicit-end"`
 		FlowSimpleCollections *bool          `yaml:"flow-simple-coll"`
+		MapKeyOrder           *string        `yaml:"map-key-order"`
 		KnownFields           *bool          `yaml:"known-fields"`
 		SingleDocument        *bool          `yaml:"single-document"`
 		UniqueKeys            *bool          `yaml:"unique-keys"`
+		CaseInsensitiveKeys   *bool          `yaml:"case-insensitive-keys"`
+		UseNumber             *bool          `yaml:"use-number"`
+		OrderedMaps           *bool          `yaml:"ordered-maps"`
+		CollectErrors         *bool          `yaml:"collect-errors"`
+		MaxErrors             *int           `yaml:"max-errors"`
+		FieldNaming           *string        `yaml:"field-naming"`
+		JSONTags              *bool          `yaml:"json-tags"`
+		Schema                *string        `yaml:"schema"`
+		SchemaFromVersion     *bool          `yaml:"schema-from-version"`
 		Plugin                map[string]any `yaml:"plugin"`
 	}
 	if err := Load([]byte(yamlStr), &cfg, WithKnownFields()); err != nil {
@@ -393,6 +595,64 @@ func OptsYAML(yamlStr string) (Option, error) {
 	if cfg.Canonical != nil {
 		optList = append(optList, WithCanonical(*cfg.Canonical))
 	}
+	if cfg.CaseInsensitiveKeys != nil {
+		optList = append(optList, WithCaseInsensitiveKeys(*cfg.CaseInsensitiveKeys))
+	}
+	if cfg.UseNumber != nil {
+		optList = append(optList, WithUseNumber(*cfg.UseNumber))
+	}
+	if cfg.OrderedMaps != nil {
+		optList = append(optList, WithOrderedMaps(*cfg.OrderedMaps))
+	}
+	if cfg.CollectErrors != nil {
+		optList = append(optList, WithCollectErrors(*cfg.CollectErrors))
+	}
+	if cfg.MaxErrors != nil {
+		optList = append(optList, WithMaxErrors(*cfg.MaxErrors))
+	}
+	if cfg.FieldNaming != nil {
+		naming, ok := map[string]FieldNaming{
+			"lower":  LowerCase,
+			"snake":  SnakeCase,
+			"kebab":  KebabCase,
+			"camel":  CamelCase,
+			"pascal": PascalCase,
+		}[*cfg.FieldNaming]
+		if !ok {
+			return nil, errors.New("yaml: invalid field-naming value (use lower, snake, kebab, camel, or pascal)")
+		}
+		optList = append(optList, WithFieldNaming(naming))
+	}
+	if cfg.JSONTags != nil {
+		optList = append(optList, WithJSONTags(*cfg.JSONTags))
+	}
+	if cfg.Schema != nil {
+		schema, ok := map[string]Schema{
+			"default":  DefaultSchema,
+			"core":     CoreSchema,
+			"json":     JSONSchema,
+			"failsafe": FailsafeSchema,
+			"yaml1.1":  YAML11Schema,
+		}[*cfg.Schema]
+		if !ok {
+			return nil, errors.New("yaml: invalid schema value (use default, core, json, failsafe, or yaml1.1)")
+		}
+		optList = append(optList, WithSchema(schema))
+	}
+	if cfg.SchemaFromVersion != nil {
+		optList = append(optList, WithSchemaFromVersion(*cfg.SchemaFromVersion))
+	}
+	if cfg.MapKeyOrder != nil {
+		order, ok := map[string]KeyOrderFunc{
+			"insertion": InsertionKeyOrder,
+			"natural":   NaturalKeyOrder,
+			"lexical":   LexicalKeyOrder,
+		}[*cfg.MapKeyOrder]
+		if !ok {
+			return nil, errors.New("yaml: invalid map-key-order value (use insertion, natural, or lexical)")
+		}
+		optList = append(optList, WithMapKeyOrder(order))
+	}
 	if cfg.LineBreak != nil {
 		switch *cfg.LineBreak {
 		case "ln":
@@ -406,7 +666,13 @@ func OptsYAML(yamlStr string) (Option, error) {
 		}
 	}
 
-	for name, val := range cfg.Plugin {
+	names := make([]string, 0, len(cfg.Plugin))
+	for name := range cfg.Plugin {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		val := cfg.Plugin[name]
 		switch name {
 		case "limit":
 			var cfgMap map[string]any
@@ -423,6 +689,36 @@ func OptsYAML(yamlStr string) (Option, error) {
 				return nil, err
 			}
 			optList = append(optList, WithPlugin(p))
+		case "interpolate":
+			var cfgMap map[string]any
+			switch v := val.(type) {
+			case nil:
+				cfgMap = map[string]any{}
+			case map[string]any:
+				cfgMap = v
+			default:
+				return nil, fmt.Errorf("yaml: plugin %q value must be a mapping or null", name)
+			}
+			p, err := interpolate.NewFromYAML(cfgMap)
+			if err != nil {
+				return nil, err
+			}
+			optList = append(optList, WithPlugin(p))
+		case "include":
+			var cfgMap map[string]any
+			switch v := val.(type) {
+			case nil:
+				cfgMap = map[string]any{}
+			case map[string]any:
+				cfgMap = v
+			default:
+				return nil, fmt.Errorf("yaml: plugin %q value must be a mapping or null", name)
+			}
+			p, err := include.NewFromYAML(cfgMap)
+			if err != nil {
+				return nil, err
+			}
+			optList = append(optList, WithPlugin(p))
 		default:
 			return nil, fmt.Errorf("yaml: unknown plugin %q", name)
 		}
@@ -486,6 +782,46 @@ const (
 // Mark represents a position in the YAML document.
 type Mark = libyaml.Mark
 
+// DeprecatedKeyFunc is called when a mapping key is decoded into a struct
+// field through an alias= key. See [WithDeprecatedKeyFunc].
+type DeprecatedKeyFunc = libyaml.DeprecatedKeyFunc
+
+// Number is a YAML integer or float kept as its decimal text, like
+// json.Number. See [WithUseNumber].
+type Number = libyaml.Number
+
+// MapSlice is a mapping that keeps its keys in order, loaded into interface
+// values with [WithOrderedMaps]. A MapSlice is dumped with its keys in the
+// order of its items. Mappings loaded into a MapSlice field, and the
+// mappings nested in it, are kept in order without the option.
+type MapSlice = libyaml.MapSlice
+
+// MapItem is a key and value of a [MapSlice].
+type MapItem = libyaml.MapItem
+
+// KeyOrderFunc reports whether mapping key a is dumped before key b.
+// See [WithMapKeyOrder].
+type KeyOrderFunc = libyaml.KeyOrderFunc
+
+// Map key orders for [WithMapKeyOrder].
+var (
+	// InsertionKeyOrder keeps the keys of a MapSlice in the order of its
+	// items, and sorts the keys of Go maps with NaturalKeyOrder. It is the
+	// default.
+	InsertionKeyOrder = libyaml.InsertionKeyOrder
+
+	// NaturalKeyOrder sorts keys with natural number ordering, so that
+	// "a2" comes before "a10".
+	NaturalKeyOrder = libyaml.NaturalKeyOrder
+
+	// LexicalKeyOrder sorts keys by comparing their bytes.
+	LexicalKeyOrder = libyaml.LexicalKeyOrder
+)
+
+// PriorityKeyOrder returns an order that puts the given keys first, in the
+// order given, followed by the other keys in natural order.
+var PriorityKeyOrder = libyaml.PriorityKeyOrder
+
 // Error types for YAML loading and dumping
 type (
 	// LoadError represents an error encountered while decoding a YAML document.
@@ -541,6 +877,32 @@ const (
 	QuoteLegacy = libyaml.QuoteLegacy // Legacy v2/v3 behavior
 )
 
+// FieldNaming derives the YAML key of a struct field from its Go name when
+// the field tag does not set a key. See [WithFieldNaming].
+type FieldNaming = libyaml.FieldNaming
+
+// Field naming policies.
+const (
+	LowerCase  = libyaml.LowerCase  // MaxRetries becomes maxretries (default)
+	SnakeCase  = libyaml.SnakeCase  // MaxRetries becomes max_retries
+	KebabCase  = libyaml.KebabCase  // MaxRetries becomes max-retries
+	CamelCase  = libyaml.CamelCase  // MaxRetries becomes maxRetries
+	PascalCase = libyaml.PascalCase // MaxRetries stays MaxRetries
+)
+
+// Schema selects the rules used to resolve the tags of plain scalars.
+// See [WithSchema].
+type Schema = libyaml.Schema
+
+// Resolution schemas.
+const (
+	DefaultSchema  = libyaml.DefaultSchema  // go-yaml rules: Core plus timestamps, 0b and 0755 (default)
+	CoreSchema     = libyaml.CoreSchema     // YAML 1.2 Core schema
+	JSONSchema     = libyaml.JSONSchema     // YAML 1.2 JSON schema
+	FailsafeSchema = libyaml.FailsafeSchema // YAML 1.2 Failsafe schema: only strings
+	YAML11Schema   = libyaml.YAML11Schema   // YAML 1.1 types: yes/no, base 60, 0755
+)
+
 //-----------------------------------------------------------------------------
 // Load/Dump API
 //-----------------------------------------------------------------------------
@@ -575,6 +937,40 @@ func Dump(in any, opts ...Option) (out []byte, err error) {
 	return libyaml.Dump(in, opts...)
 }
 
+// LoadAs loads a YAML document into a new value of type T and returns it.
+// It accepts the same options as [Load].
+//
+//	cfg, err := yaml.LoadAs[Config](data)
+func LoadAs[T any](in []byte, opts ...Option) (T, error) {
+	return libyaml.LoadAs[T](in, opts...)
+}
+
+// DumpAll encodes each value in docs as a separate YAML document, like
+// [Dump] with [WithAllDocuments].
+func DumpAll[T any](docs []T, opts ...Option) ([]byte, error) {
+	return libyaml.DumpAll(docs, opts...)
+}
+
+// DocumentsAs returns an iterator over the remaining documents of the
+// stream read by l, loaded into values of type T. It has the shape of
+// iter.Seq2[T, error], so with Go 1.23 or later it can be ranged over:
+//
+//	for cfg, err := range yaml.DocumentsAs[Config](loader) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    ...
+//	}
+//
+// The iteration ends at the end of the stream, without an error. A
+// document with values that could not be loaded into T is yielded with its
+// *LoadErrors and the iteration continues; it ends after yielding any
+// other error, such as a syntax error. See [Loader.Documents] for an
+// iterator over document nodes.
+func DocumentsAs[T any](l *Loader) func(yield func(T, error) bool) {
+	return libyaml.DocumentsAs[T](l)
+}
+
 //-----------------------------------------------------------------------------
 // Classic APIs
 //-----------------------------------------------------------------------------
@@ -669,6 +1065,10 @@ func (e *Encoder) Close() error {
 // content, and a *yaml.LoadErrors is returned with details for all
 // missed values.
 //
+// Numbers may be decoded into big.Int, big.Float and big.Rat values
+// without losing precision, and those types are marshaled as plain YAML
+// numbers.
+//
 // Struct fields are only unmarshalled if they are exported (have an
 // upper case first letter), and are unmarshalled using the field name
 // lowercased as the default key. Custom keys may be defined via the
@@ -726,6 +1126,13 @@ func withFromLegacy() Option {
 //	             method (see the IsZeroer interface type), in which
 //	             case the field will be excluded if IsZero returns true.
 //
+//	omitzero     Only include the field if it's not the zero value for
+//	             its type, or if the IsZero method of the field type
+//	             (see the IsZeroer interface type), on the value or on
+//	             a pointer to it, returns false. Unlike omitempty, empty
+//	             non-nil slices and maps are included, as with omitzero
+//	             in encoding/json. Both flags may be combined.
+//
 //	flow         Marshal using a flow style (useful for structs,
 //	             sequences and maps).
 //
@@ -735,8 +1142,26 @@ func withFromLegacy() Option {
 //	             not conflict with the yaml keys of other struct fields.
 //	             See doc/inline-tags.md for detailed examples and use cases.
 //
+//	required     When unmarshaling, report an error at the position of the
+//	             mapping if the key is missing from it. Keys merged with
+//	             << count as present.
+//
+//	alias=K      When unmarshaling, also accept the key K for the field,
+//	             such as an old name of the key. The flag may be repeated.
+//	             See WithDeprecatedKeyFunc to report aliased keys.
+//
+//	default=V    When unmarshaling, decode the YAML value V into the field
+//	             if the key is missing and the field is still zero. V
+//	             runs to the end of the tag, so it may contain commas:
+//	             `yaml:"ports,default=[80, 443]"`.
+//
+// The required, default and alias flags are ignored when marshaling.
+//
 // In addition, if the key is "-", the field is ignored.
 //
+// With the WithJSONTags option, the json tag of a field is used when it has
+// no yaml tag.
+//
 // For example:
 //
 //	type T struct {
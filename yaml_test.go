@@ -2024,6 +2024,16 @@ func init() {
 	encodeValueRegistry.Register("-0", negativeZero)
 }
 
+// evenIsZero is zero when even, for testing omitzero.
+type evenIsZero int
+
+func (z evenIsZero) IsZero() bool { return z%2 == 0 }
+
+// ptrEvenIsZero is evenIsZero with a pointer receiver.
+type ptrEvenIsZero int
+
+func (z *ptrEvenIsZero) IsZero() bool { return *z%2 == 0 }
+
 var marshalTests = []struct {
 	value any
 	data  string
@@ -2111,6 +2121,34 @@ var marshalTests = []struct {
 		},
 		"t2: 2018-01-09T10:40:47Z\nt4: 2098-01-09T10:40:47Z\n",
 	},
+	{
+		&struct {
+			A []int          `yaml:"a,omitzero"`
+			B []int          `yaml:"b,omitzero"`
+			C map[string]int `yaml:"c,omitzero"`
+			D int            `yaml:"d,omitzero"`
+		}{A: []int{}},
+		"a: []\n",
+	},
+	{
+		&struct {
+			A struct{ X, y int } `yaml:"a,omitzero,flow"`
+			B struct{ X, y int } `yaml:"b,omitzero,flow"`
+		}{A: struct{ X, y int }{0, 1}},
+		"a: {x: 0}\n",
+	},
+	{
+		&struct {
+			T1 time.Time     `yaml:"t1,omitzero"`
+			T2 *time.Time    `yaml:"t2,omitzero"`
+			Z1 evenIsZero    `yaml:"z1,omitzero"`
+			Z2 evenIsZero    `yaml:"z2,omitzero"`
+			Z3 *evenIsZero   `yaml:"z3,omitzero"`
+			Z4 ptrEvenIsZero `yaml:"z4,omitzero"`
+			Z5 ptrEvenIsZero `yaml:"z5,omitzero"`
+		}{Z1: 2, Z2: 3, Z4: 4, Z5: 5},
+		"z2: 3\nz5: 5\n",
+	},
 	// Nil interface that implements Marshaler.
 	{
 		map[string]yaml.Marshaler{
@@ -3155,12 +3193,40 @@ line-break: ln
 explicit-start: true
 explicit-end: false
 flow-simple-coll: true
+map-key-order: lexical
 known-fields: true
 single-document: true
 unique-keys: true
+case-insensitive-keys: true
+use-number: true
+ordered-maps: true
+collect-errors: true
+max-errors: 10
+field-naming: kebab
+json-tags: true
+schema: yaml1.1
+schema-from-version: true
 `,
 			expectErr: false,
 		},
+		{
+			name:      "invalid field naming",
+			yamlStr:   "field-naming: shouty",
+			expectErr: true,
+			errMatch:  "invalid field-naming value",
+		},
+		{
+			name:      "invalid schema",
+			yamlStr:   "schema: yaml1.3",
+			expectErr: true,
+			errMatch:  "invalid schema value",
+		},
+		{
+			name:      "invalid map key order",
+			yamlStr:   "map-key-order: random",
+			expectErr: true,
+			errMatch:  "invalid map-key-order value",
+		},
 	}
 
 	for _, tt := range tests {